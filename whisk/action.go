@@ -18,6 +18,7 @@
 package whisk
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -165,6 +166,10 @@ func (action Action) ActionURL(apiHost string, apiPath string, apiVersion string
 ////////////////////
 
 func (s *ActionService) List(packageName string, options *ActionListOptions) ([]Action, *http.Response, error) {
+	return s.ListContext(context.Background(), packageName, options)
+}
+
+func (s *ActionService) ListContext(ctx context.Context, packageName string, options *ActionListOptions) ([]Action, *http.Response, error) {
 	var route string
 	var actions []Action
 
@@ -177,20 +182,20 @@ func (s *ActionService) List(packageName string, options *ActionListOptions) ([]
 		route = fmt.Sprintf("actions")
 	}
 
-	routeUrl, err := addRouteOptions(route, options)
+	routeUrl, err := addRouteOptions(s.client, route, options)
 	if err != nil {
-		Debug(DbgError, "addRouteOptions(%s, %#v) error: '%s'\n", route, options, err)
+		debugOf(s.client, DbgError, "addRouteOptions(%s, %#v) error: '%s'\n", route, options, err)
 		errMsg := wski18n.T("Unable to add route options '{{.options}}'",
 			map[string]interface{}{"options": options})
 		whiskErr := MakeWskErrorFromWskError(errors.New(errMsg), err, EXIT_CODE_ERR_GENERAL, DISPLAY_MSG,
 			NO_DISPLAY_USAGE)
 		return nil, nil, whiskErr
 	}
-	Debug(DbgError, "Action list route with options: %s\n", route)
+	debugOf(s.client, DbgError, "Action list route with options: %s\n", route)
 
 	req, err := s.client.NewRequestUrl("GET", routeUrl, nil, IncludeNamespaceInUrl, AppendOpenWhiskPathPrefix, EncodeBodyAsJson, AuthRequired)
 	if err != nil {
-		Debug(DbgError, "http.NewRequestUrl(GET, %s, nil, IncludeNamespaceInUrl, AppendOpenWhiskPathPrefix, EncodeBodyAsJson, AuthRequired) error: '%s'\n", routeUrl, err)
+		debugOf(s.client, DbgError, "http.NewRequestUrl(GET, %s, nil, IncludeNamespaceInUrl, AppendOpenWhiskPathPrefix, EncodeBodyAsJson, AuthRequired) error: '%s'\n", routeUrl, err)
 		errMsg := wski18n.T("Unable to create HTTP request for GET '{{.route}}': {{.err}}",
 			map[string]interface{}{"route": routeUrl, "err": err})
 		whiskErr := MakeWskErrorFromWskError(errors.New(errMsg), err, EXIT_CODE_ERR_NETWORK, DISPLAY_MSG,
@@ -198,9 +203,9 @@ func (s *ActionService) List(packageName string, options *ActionListOptions) ([]
 		return nil, nil, whiskErr
 	}
 
-	resp, err := s.client.Do(req, &actions, ExitWithSuccessOnTimeout)
+	resp, err := doContext(s.client, ctx, req, &actions, ExitWithSuccessOnTimeout)
 	if err != nil {
-		Debug(DbgError, "s.client.Do() error - HTTP req %s; error '%s'\n", req.URL.String(), err)
+		debugOf(s.client, DbgError, "s.client.Do() error - HTTP req %s; error '%s'\n", redactURL(s.client, req), err)
 		return nil, resp, err
 	}
 
@@ -208,15 +213,19 @@ func (s *ActionService) List(packageName string, options *ActionListOptions) ([]
 }
 
 func (s *ActionService) Insert(action *Action, overwrite bool) (*Action, *http.Response, error) {
+	return s.InsertContext(context.Background(), action, overwrite)
+}
+
+func (s *ActionService) InsertContext(ctx context.Context, action *Action, overwrite bool) (*Action, *http.Response, error) {
 	// Encode resource name as a path (with no query params) before inserting it into the URI
 	// This way any '?' chars in the name won't be treated as the beginning of the query params
 	actionName := (&url.URL{Path: action.Name}).String()
 	route := fmt.Sprintf("actions/%s?overwrite=%t", actionName, overwrite)
-	Debug(DbgInfo, "Action insert route: %s\n", route)
+	debugOf(s.client, DbgInfo, "Action insert route: %s\n", route)
 
 	req, err := s.client.NewRequest("PUT", route, action, IncludeNamespaceInUrl)
 	if err != nil {
-		Debug(DbgError, "http.NewRequest(PUT, %s, %#v) error: '%s'\n", route, action, err)
+		debugOf(s.client, DbgError, "http.NewRequest(PUT, %s, %#v) error: '%s'\n", route, action, err)
 		errMsg := wski18n.T("Unable to create HTTP request for PUT '{{.route}}': {{.err}}",
 			map[string]interface{}{"route": route, "err": err})
 		whiskErr := MakeWskErrorFromWskError(errors.New(errMsg), err, EXIT_CODE_ERR_NETWORK, DISPLAY_MSG,
@@ -225,9 +234,9 @@ func (s *ActionService) Insert(action *Action, overwrite bool) (*Action, *http.R
 	}
 
 	a := new(Action)
-	resp, err := s.client.Do(req, &a, ExitWithSuccessOnTimeout)
+	resp, err := doContext(s.client, ctx, req, &a, ExitWithSuccessOnTimeout)
 	if err != nil {
-		Debug(DbgError, "s.client.Do() error - HTTP req %s; error '%s'\n", req.URL.String(), err)
+		debugOf(s.client, DbgError, "s.client.Do() error - HTTP req %s; error '%s'\n", redactURL(s.client, req), err)
 		return nil, resp, err
 	}
 
@@ -235,6 +244,10 @@ func (s *ActionService) Insert(action *Action, overwrite bool) (*Action, *http.R
 }
 
 func (s *ActionService) Get(actionName string, fetchCode bool) (*Action, *http.Response, error) {
+	return s.GetContext(context.Background(), actionName, fetchCode)
+}
+
+func (s *ActionService) GetContext(ctx context.Context, actionName string, fetchCode bool) (*Action, *http.Response, error) {
 	// Encode resource name as a path (with no query params) before inserting it into the URI
 	// This way any '?' chars in the name won't be treated as the beginning of the query params
 	actionName = (&url.URL{Path: actionName}).String()
@@ -242,7 +255,7 @@ func (s *ActionService) Get(actionName string, fetchCode bool) (*Action, *http.R
 
 	req, err := s.client.NewRequest("GET", route, nil, IncludeNamespaceInUrl)
 	if err != nil {
-		Debug(DbgError, "http.NewRequest(GET, %s, nil) error: '%s'\n", route, err)
+		debugOf(s.client, DbgError, "http.NewRequest(GET, %s, nil) error: '%s'\n", route, err)
 		errMsg := wski18n.T("Unable to create HTTP request for GET '{{.route}}': {{.err}}",
 			map[string]interface{}{"route": route, "err": err})
 		whiskErr := MakeWskErrorFromWskError(errors.New(errMsg), err, EXIT_CODE_ERR_NETWORK, DISPLAY_MSG,
@@ -251,9 +264,9 @@ func (s *ActionService) Get(actionName string, fetchCode bool) (*Action, *http.R
 	}
 
 	a := new(Action)
-	resp, err := s.client.Do(req, &a, ExitWithSuccessOnTimeout)
+	resp, err := doContext(s.client, ctx, req, &a, ExitWithSuccessOnTimeout)
 	if err != nil {
-		Debug(DbgError, "s.client.Do() error - HTTP req %s; error '%s'\n", req.URL.String(), err)
+		debugOf(s.client, DbgError, "s.client.Do() error - HTTP req %s; error '%s'\n", redactURL(s.client, req), err)
 		return nil, resp, err
 	}
 
@@ -261,15 +274,19 @@ func (s *ActionService) Get(actionName string, fetchCode bool) (*Action, *http.R
 }
 
 func (s *ActionService) Delete(actionName string) (*http.Response, error) {
+	return s.DeleteContext(context.Background(), actionName)
+}
+
+func (s *ActionService) DeleteContext(ctx context.Context, actionName string) (*http.Response, error) {
 	// Encode resource name as a path (with no query params) before inserting it into the URI
 	// This way any '?' chars in the name won't be treated as the beginning of the query params
 	actionName = (&url.URL{Path: actionName}).String()
 	route := fmt.Sprintf("actions/%s", actionName)
-	Debug(DbgInfo, "HTTP route: %s\n", route)
+	debugOf(s.client, DbgInfo, "HTTP route: %s\n", route)
 
 	req, err := s.client.NewRequest("DELETE", route, nil, IncludeNamespaceInUrl)
 	if err != nil {
-		Debug(DbgError, "http.NewRequest(DELETE, %s, nil) error: '%s'\n", route, err)
+		debugOf(s.client, DbgError, "http.NewRequest(DELETE, %s, nil) error: '%s'\n", route, err)
 		errMsg := wski18n.T("Unable to create HTTP request for DELETE '{{.route}}': {{.err}}",
 			map[string]interface{}{"route": route, "err": err})
 		whiskErr := MakeWskErrorFromWskError(errors.New(errMsg), err, EXIT_CODE_ERR_NETWORK, DISPLAY_MSG,
@@ -278,9 +295,9 @@ func (s *ActionService) Delete(actionName string) (*http.Response, error) {
 	}
 
 	a := new(Action)
-	resp, err := s.client.Do(req, a, ExitWithSuccessOnTimeout)
+	resp, err := doContext(s.client, ctx, req, a, ExitWithSuccessOnTimeout)
 	if err != nil {
-		Debug(DbgError, "s.client.Do() error - HTTP req %s; error '%s'\n", req.URL.String(), err)
+		debugOf(s.client, DbgError, "s.client.Do() error - HTTP req %s; error '%s'\n", redactURL(s.client, req), err)
 		return resp, err
 	}
 
@@ -288,17 +305,21 @@ func (s *ActionService) Delete(actionName string) (*http.Response, error) {
 }
 
 func (s *ActionService) Invoke(actionName string, payload interface{}, blocking bool, result bool) (map[string]interface{}, *http.Response, error) {
+	return s.InvokeContext(context.Background(), actionName, payload, blocking, result)
+}
+
+func (s *ActionService) InvokeContext(ctx context.Context, actionName string, payload interface{}, blocking bool, result bool) (map[string]interface{}, *http.Response, error) {
 	var res map[string]interface{}
 
 	// Encode resource name as a path (with no query params) before inserting it into the URI
 	// This way any '?' chars in the name won't be treated as the beginning of the query params
 	actionName = (&url.URL{Path: actionName}).String()
 	route := fmt.Sprintf("actions/%s?blocking=%t&result=%t", actionName, blocking, result)
-	Debug(DbgInfo, "HTTP route: %s\n", route)
+	debugOf(s.client, DbgInfo, "HTTP route: %s\n", route)
 
 	req, err := s.client.NewRequest("POST", route, payload, IncludeNamespaceInUrl)
 	if err != nil {
-		Debug(DbgError, "http.NewRequest(POST, %s, %#v) error: '%s'\n", route, payload, err)
+		debugOf(s.client, DbgError, "http.NewRequest(POST, %s, %#v) error: '%s'\n", route, payload, err)
 		errMsg := wski18n.T("Unable to create HTTP request for POST '{{.route}}': {{.err}}",
 			map[string]interface{}{"route": route, "err": err})
 		whiskErr := MakeWskErrorFromWskError(errors.New(errMsg), err, EXIT_CODE_ERR_NETWORK, DISPLAY_MSG,
@@ -306,12 +327,76 @@ func (s *ActionService) Invoke(actionName string, payload interface{}, blocking
 		return nil, nil, whiskErr
 	}
 
-	resp, err := s.client.Do(req, &res, blocking)
+	resp, err := doContext(s.client, ctx, req, &res, blocking)
 
 	if err != nil {
-		Debug(DbgError, "s.client.Do() error - HTTP req %s; error '%s'\n", req.URL.String(), err)
+		debugOf(s.client, DbgError, "s.client.Do() error - HTTP req %s; error '%s'\n", redactURL(s.client, req), err)
 		return res, resp, err
 	}
 
 	return res, resp, nil
 }
+
+// InvokeAndWait invokes the action as a blocking invocation and returns its activation record.  When the
+// invocation outlasts the controller's blocking window, the controller answers with only the activation id;
+// InvokeAndWait then keeps polling for the record with ActivationService.Wait until it is available or the
+// options' Timeout expires.  The Timeout covers the whole call, including the blocking request.
+//
+// The activation record is returned the same way whichever path was taken: an action that completed with an
+// application or developer error is not reported as an error, check the record's Response instead.
+func (s *ActionService) InvokeAndWait(actionName string, payload interface{}, options *WaitOptions) (*Activation, *http.Response, error) {
+	return s.InvokeAndWaitContext(context.Background(), actionName, payload, options)
+}
+
+func (s *ActionService) InvokeAndWaitContext(ctx context.Context, actionName string, payload interface{}, options *WaitOptions) (*Activation, *http.Response, error) {
+	var opts WaitOptions
+	if options != nil {
+		opts = *options
+	}
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+		opts.Timeout = 0
+	}
+
+	activation := new(Activation)
+
+	// Encode resource name as a path (with no query params) before inserting it into the URI
+	// This way any '?' chars in the name won't be treated as the beginning of the query params
+	actionName = (&url.URL{Path: actionName}).String()
+	route := fmt.Sprintf("actions/%s?blocking=true&result=false", actionName)
+	debugOf(s.client, DbgInfo, "HTTP route: %s\n", route)
+
+	req, err := s.client.NewRequest("POST", route, payload, IncludeNamespaceInUrl)
+	if err != nil {
+		debugOf(s.client, DbgError, "http.NewRequest(POST, %s, %#v) error: '%s'\n", route, payload, err)
+		errMsg := wski18n.T("Unable to create HTTP request for POST '{{.route}}': {{.err}}",
+			map[string]interface{}{"route": route, "err": err})
+		whiskErr := MakeWskErrorFromWskError(errors.New(errMsg), err, EXIT_CODE_ERR_NETWORK, DISPLAY_MSG,
+			NO_DISPLAY_USAGE)
+		return nil, nil, whiskErr
+	}
+
+	resp, err := doContext(s.client, ctx, req, activation, ExitWithErrorOnTimeout)
+	if err == nil {
+		return activation, resp, nil
+	}
+
+	switch {
+	case errors.Is(err, ErrApplication) && len(activation.ActivationID) > 0:
+		// The action completed, but failed; the activation record is the result
+		debugOf(s.client, DbgInfo, "Activation '%s' completed with an application error\n", activation.ActivationID)
+		return activation, resp, nil
+	case errors.Is(err, ErrTimedOut) && len(activation.ActivationID) > 0:
+		// Polling needs the activation endpoints, which are only available from a *Client
+		if client, isClient := s.client.(*Client); isClient {
+			debugOf(s.client, DbgInfo, "Blocking invocation timed out; polling for activation '%s'\n", activation.ActivationID)
+			return client.Activations.WaitContext(ctx, activation.ActivationID, &opts)
+		}
+		return activation, resp, err
+	}
+
+	debugOf(s.client, DbgError, "s.client.Do() error - HTTP req %s; error '%s'\n", redactURL(s.client, req), err)
+	return nil, resp, err
+}
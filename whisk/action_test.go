@@ -24,9 +24,11 @@ import (
 	"github.com/stretchr/testify/assert"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"strings"
 	"testing"
+	"time"
 )
 
 const (
@@ -149,3 +151,80 @@ func TestActionGet(t *testing.T) {
 	assert.Equal("actions/test?code=true", actionRequest.URL)
 	assert.Equal("...", *action.Exec.Code)
 }
+
+// newInvokeServer answers the blocking invocation with `status` and `body`, and activation requests with the
+// activation record after `missing` 404 responses.
+func newInvokeServer(status int, body string, missing int, polls *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == "POST" {
+			w.WriteHeader(status)
+			w.Write([]byte(body))
+			return
+		}
+		*polls++
+		if *polls <= missing {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(ACTIVATION_NOT_FOUND))
+			return
+		}
+		w.Write([]byte(ACTIVATION_RECORD))
+	}))
+}
+
+func TestActionInvokeAndWaitBlocking(t *testing.T) {
+	polls := 0
+	server := newInvokeServer(http.StatusOK, ACTIVATION_RECORD, 0, &polls)
+	defer server.Close()
+	client := newLocalTestClient(t, server, nil)
+
+	activation, resp, err := client.Actions.InvokeAndWait("test", nil, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "f00ba7", activation.ActivationID)
+	assert.Equal(t, 0, polls)
+}
+
+func TestActionInvokeAndWaitFallsBackToPolling(t *testing.T) {
+	polls := 0
+	server := newInvokeServer(http.StatusAccepted, `{"activationId":"f00ba7"}`, 2, &polls)
+	defer server.Close()
+	client := newLocalTestClient(t, server, nil)
+
+	activation, resp, err := client.Actions.InvokeAndWait("test", nil, &WaitOptions{InitialInterval: time.Millisecond})
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "f00ba7", activation.ActivationID)
+	assert.Equal(t, true, activation.Response.Success)
+	assert.Equal(t, 3, polls)
+}
+
+func TestActionInvokeAndWaitApplicationError(t *testing.T) {
+	polls := 0
+	record := `{"activationId":"f00ba7","response":{"status":"application error","success":false,"result":{"error":"boom"}}}`
+	server := newInvokeServer(http.StatusBadGateway, record, 0, &polls)
+	defer server.Close()
+	client := newLocalTestClient(t, server, nil)
+
+	activation, _, err := client.Actions.InvokeAndWait("test", nil, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "f00ba7", activation.ActivationID)
+	assert.Equal(t, false, activation.Response.Success)
+	assert.Equal(t, "application error", activation.Response.Status)
+}
+
+func TestActionInvokeAndWaitTimeout(t *testing.T) {
+	polls := 0
+	server := newInvokeServer(http.StatusAccepted, `{"activationId":"f00ba7"}`, 1000, &polls)
+	defer server.Close()
+	client := newLocalTestClient(t, server, nil)
+
+	_, _, err := client.Actions.InvokeAndWait("test", nil, &WaitOptions{
+		Timeout:         50 * time.Millisecond,
+		InitialInterval: 5 * time.Millisecond,
+	})
+	assert.NotNil(t, err)
+	whiskErr, ok := err.(*WskError)
+	assert.True(t, ok)
+	assert.True(t, whiskErr.TimedOut)
+}
@@ -18,6 +18,7 @@
 package whisk
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/apache/openwhisk-client-go/wski18n"
@@ -72,6 +73,18 @@ type ActivationListOptions struct {
 	Docs  bool   `url:"docs,omitempty"`
 }
 
+// WaitOptions controls how ActivationService.Wait polls for an activation record
+type WaitOptions struct {
+	Timeout         time.Duration // Give up after this duration; zero waits until the context is done
+	InitialInterval time.Duration // Delay after the first unsuccessful poll; doubled after each poll.  Default is 500ms
+	MaxInterval     time.Duration // Upper bound of the delay between two polls.  Default is 5s
+}
+
+const (
+	DEFAULT_WAIT_INITIAL_INTERVAL = 500 * time.Millisecond
+	DEFAULT_WAIT_MAX_INTERVAL     = 5 * time.Second
+)
+
 //MWD - This structure may no longer be needed as the log format is now a string and not JSON
 type Log struct {
 	Log    string `json:"log,omitempty"`
@@ -155,34 +168,47 @@ func (activation ActivationFilteredRow) ToSummaryRowString() string {
 		fqn)
 }
 
+// namespacedRoute returns the route of an activation request, including its namespace: the requests are sent to
+// "_", for which /activations always worked, unless the namespace was chosen with Client.WithNamespace.
+func (s *ActivationService) namespacedRoute(route string) string {
+	namespace := "_"
+	if s.client.namespaced {
+		namespace = s.client.Config.Namespace
+	}
+	// Encode the namespace as a path, as NewRequestUrl does, so that any '?' is not the start of the query params
+	return fmt.Sprintf("namespaces/%s/%s", (&url.URL{Path: namespace}).String(), route)
+}
+
 func (s *ActivationService) List(options *ActivationListOptions) ([]Activation, *http.Response, error) {
-	// TODO :: for some reason /activations only works with "_" as namespace
-	s.client.Namespace = "_"
-	route := "activations"
-	routeUrl, err := addRouteOptions(route, options)
+	return s.ListContext(context.Background(), options)
+}
+
+func (s *ActivationService) ListContext(ctx context.Context, options *ActivationListOptions) ([]Activation, *http.Response, error) {
+	route := s.namespacedRoute("activations")
+	routeUrl, err := addRouteOptions(s.client, route, options)
 	if err != nil {
-		Debug(DbgError, "addRouteOptions(%s, %#v) error: '%s'\n", route, options, err)
+		s.client.debug(DbgError, "addRouteOptions(%s, %#v) error: '%s'\n", route, options, err)
 		errStr := wski18n.T("Unable to append options '{{.options}}' to URL route '{{.route}}': {{.err}}",
 			map[string]interface{}{"options": fmt.Sprintf("%#v", options), "route": route, "err": err})
 		werr := MakeWskErrorFromWskError(errors.New(errStr), err, EXIT_CODE_ERR_GENERAL, DISPLAY_MSG, NO_DISPLAY_USAGE)
 		return nil, nil, werr
 	}
 
-	req, err := s.client.NewRequestUrl("GET", routeUrl, nil, IncludeNamespaceInUrl, AppendOpenWhiskPathPrefix, EncodeBodyAsJson, AuthRequired)
+	req, err := s.client.NewRequestUrl("GET", routeUrl, nil, DoNotIncludeNamespaceInUrl, AppendOpenWhiskPathPrefix, EncodeBodyAsJson, AuthRequired)
 	if err != nil {
-		Debug(DbgError, "http.NewRequestUrl(GET, %s, nil, IncludeNamespaceInUrl, AppendOpenWhiskPathPrefix, EncodeBodyAsJson, AuthRequired) error: '%s'\n", route, err)
+		s.client.debug(DbgError, "http.NewRequestUrl(GET, %s, nil, DoNotIncludeNamespaceInUrl, AppendOpenWhiskPathPrefix, EncodeBodyAsJson, AuthRequired) error: '%s'\n", route, err)
 		errStr := wski18n.T("Unable to create HTTP request for GET '{{.route}}': {{.err}}",
 			map[string]interface{}{"route": route, "err": err})
 		werr := MakeWskErrorFromWskError(errors.New(errStr), err, EXIT_CODE_ERR_GENERAL, DISPLAY_MSG, NO_DISPLAY_USAGE)
 		return nil, nil, werr
 	}
 
-	Debug(DbgInfo, "Sending HTTP request - URL '%s'; req %#v\n", req.URL.String(), req)
+	s.client.debug(DbgInfo, "Sending HTTP request - URL '%s'\n", s.client.redactedURL(req))
 
 	var activations []Activation
-	resp, err := s.client.Do(req, &activations, ExitWithSuccessOnTimeout)
+	resp, err := s.client.DoContext(ctx, req, &activations, ExitWithSuccessOnTimeout)
 	if err != nil {
-		Debug(DbgError, "s.client.Do() error - HTTP req %s; error '%s'\n", req.URL.String(), err)
+		s.client.debug(DbgError, "s.client.Do() error - HTTP req %s; error '%s'\n", s.client.redactedURL(req), err)
 		return nil, resp, err
 	}
 
@@ -190,29 +216,30 @@ func (s *ActivationService) List(options *ActivationListOptions) ([]Activation,
 }
 
 func (s *ActivationService) Get(activationID string) (*Activation, *http.Response, error) {
-	// TODO :: for some reason /activations/:id only works with "_" as namespace
-	s.client.Namespace = "_"
+	return s.GetContext(context.Background(), activationID)
+}
 
+func (s *ActivationService) GetContext(ctx context.Context, activationID string) (*Activation, *http.Response, error) {
 	// Encode resource name as a path (with no query params) before inserting it into the URI
 	// This way any '?' chars in the name won't be treated as the beginning of the query params
 	activationID = (&url.URL{Path: activationID}).String()
-	route := fmt.Sprintf("activations/%s", activationID)
+	route := s.namespacedRoute(fmt.Sprintf("activations/%s", activationID))
 
-	req, err := s.client.NewRequest("GET", route, nil, IncludeNamespaceInUrl)
+	req, err := s.client.NewRequest("GET", route, nil, DoNotIncludeNamespaceInUrl)
 	if err != nil {
-		Debug(DbgError, "http.NewRequest(GET, %s) error: '%s'\n", route, err)
+		s.client.debug(DbgError, "http.NewRequest(GET, %s) error: '%s'\n", route, err)
 		errStr := wski18n.T("Unable to create HTTP request for GET '{{.route}}': {{.err}}",
 			map[string]interface{}{"route": route, "err": err})
 		werr := MakeWskErrorFromWskError(errors.New(errStr), err, EXIT_CODE_ERR_GENERAL, DISPLAY_MSG, NO_DISPLAY_USAGE)
 		return nil, nil, werr
 	}
 
-	Debug(DbgInfo, "Sending HTTP request - URL '%s'; req %#v\n", req.URL.String(), req)
+	s.client.debug(DbgInfo, "Sending HTTP request - URL '%s'\n", s.client.redactedURL(req))
 
 	a := new(Activation)
-	resp, err := s.client.Do(req, &a, ExitWithSuccessOnTimeout)
+	resp, err := s.client.DoContext(ctx, req, &a, ExitWithSuccessOnTimeout)
 	if err != nil {
-		Debug(DbgError, "s.client.Do() error - HTTP req %s; error '%s'\n", req.URL.String(), err)
+		s.client.debug(DbgError, "s.client.Do() error - HTTP req %s; error '%s'\n", s.client.redactedURL(req), err)
 		return nil, resp, err
 	}
 
@@ -221,6 +248,66 @@ func (s *ActivationService) Get(activationID string) (*Activation, *http.Respons
 	return a, resp, nil
 }
 
+// Wait polls for the activation record of activationID until it is available, and returns it.  The
+// record is only persisted once the activation completes, so the "not found" errors returned until
+// then are expected and ignored.  Any other error ends the wait.
+func (s *ActivationService) Wait(activationID string, options *WaitOptions) (*Activation, *http.Response, error) {
+	return s.WaitContext(context.Background(), activationID, options)
+}
+
+func (s *ActivationService) WaitContext(ctx context.Context, activationID string, options *WaitOptions) (*Activation, *http.Response, error) {
+	var opts WaitOptions
+	if options != nil {
+		opts = *options
+	}
+	if opts.InitialInterval <= 0 {
+		opts.InitialInterval = DEFAULT_WAIT_INITIAL_INTERVAL
+	}
+	if opts.MaxInterval <= 0 {
+		opts.MaxInterval = DEFAULT_WAIT_MAX_INTERVAL
+	}
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	interval := opts.InitialInterval
+	for {
+		activation, resp, err := s.GetContext(ctx, activationID)
+		if err == nil {
+			return activation, resp, nil
+		}
+		if ctx.Err() != nil {
+			return nil, resp, makeWaitError(activationID, ctx.Err())
+		}
+		if !errors.Is(err, ErrNotFound) {
+			s.client.debug(DbgError, "Waiting for activation '%s' failed: %s\n", activationID, err)
+			return nil, resp, err
+		}
+
+		s.client.debug(DbgInfo, "Activation '%s' is not available yet; polling again in %v\n", activationID, interval)
+		if err = sleepContext(ctx, interval); err != nil {
+			return nil, resp, makeWaitError(activationID, err)
+		}
+
+		interval *= 2
+		if interval > opts.MaxInterval {
+			interval = opts.MaxInterval
+		}
+	}
+}
+
+func makeWaitError(activationID string, err error) error {
+	errStr := wski18n.T("Unable to get the result of activation '{{.id}}': {{.err}}",
+		map[string]interface{}{"id": activationID, "err": err})
+	if err == context.DeadlineExceeded {
+		return MakeWskError(errors.New(errStr), EXIT_CODE_TIMED_OUT, DISPLAY_MSG, NO_DISPLAY_USAGE,
+			NO_MSG_DISPLAYED, DISPLAY_PREFIX, NO_APPLICATION_ERR, TIMED_OUT)
+	}
+	return MakeWskError(errors.New(errStr), EXIT_CODE_ERR_GENERAL, DISPLAY_MSG, NO_DISPLAY_USAGE)
+}
+
 func GetStatusCodeForMessage(msg string) int {
 	var code int
 
@@ -239,28 +326,30 @@ func GetStatusCodeForMessage(msg string) int {
 }
 
 func (s *ActivationService) Logs(activationID string) (*Activation, *http.Response, error) {
-	// TODO :: for some reason /activations/:id/logs only works with "_" as namespace
-	s.client.Namespace = "_"
+	return s.LogsContext(context.Background(), activationID)
+}
+
+func (s *ActivationService) LogsContext(ctx context.Context, activationID string) (*Activation, *http.Response, error) {
 	// Encode resource name as a path (with no query params) before inserting it into the URI
 	// This way any '?' chars in the name won't be treated as the beginning of the query params
 	activationID = (&url.URL{Path: activationID}).String()
-	route := fmt.Sprintf("activations/%s/logs", activationID)
+	route := s.namespacedRoute(fmt.Sprintf("activations/%s/logs", activationID))
 
-	req, err := s.client.NewRequest("GET", route, nil, IncludeNamespaceInUrl)
+	req, err := s.client.NewRequest("GET", route, nil, DoNotIncludeNamespaceInUrl)
 	if err != nil {
-		Debug(DbgError, "http.NewRequest(GET, %s) error: '%s'\n", route, err)
+		s.client.debug(DbgError, "http.NewRequest(GET, %s) error: '%s'\n", route, err)
 		errStr := wski18n.T("Unable to create HTTP request for GET '{{.route}}': {{.err}}",
 			map[string]interface{}{"route": route, "err": err})
 		werr := MakeWskErrorFromWskError(errors.New(errStr), err, EXIT_CODE_ERR_GENERAL, DISPLAY_MSG, NO_DISPLAY_USAGE)
 		return nil, nil, werr
 	}
 
-	Debug(DbgInfo, "Sending HTTP request - URL '%s'; req %#v\n", req.URL.String(), req)
+	s.client.debug(DbgInfo, "Sending HTTP request - URL '%s'\n", s.client.redactedURL(req))
 
 	activation := new(Activation)
-	resp, err := s.client.Do(req, &activation, ExitWithSuccessOnTimeout)
+	resp, err := s.client.DoContext(ctx, req, &activation, ExitWithSuccessOnTimeout)
 	if err != nil {
-		Debug(DbgError, "s.client.Do() error - HTTP req %s; error '%s'\n", req.URL.String(), err)
+		s.client.debug(DbgError, "s.client.Do() error - HTTP req %s; error '%s'\n", s.client.redactedURL(req), err)
 		return nil, resp, err
 	}
 
@@ -268,28 +357,30 @@ func (s *ActivationService) Logs(activationID string) (*Activation, *http.Respon
 }
 
 func (s *ActivationService) Result(activationID string) (*Response, *http.Response, error) {
-	// TODO :: for some reason /activations only works with "_" as namespace
-	s.client.Namespace = "_"
+	return s.ResultContext(context.Background(), activationID)
+}
+
+func (s *ActivationService) ResultContext(ctx context.Context, activationID string) (*Response, *http.Response, error) {
 	// Encode resource name as a path (with no query params) before inserting it into the URI
 	// This way any '?' chars in the name won't be treated as the beginning of the query params
 	activationID = (&url.URL{Path: activationID}).String()
-	route := fmt.Sprintf("activations/%s/result", activationID)
+	route := s.namespacedRoute(fmt.Sprintf("activations/%s/result", activationID))
 
-	req, err := s.client.NewRequest("GET", route, nil, IncludeNamespaceInUrl)
+	req, err := s.client.NewRequest("GET", route, nil, DoNotIncludeNamespaceInUrl)
 	if err != nil {
-		Debug(DbgError, "http.NewRequest(GET, %s) error: '%s'\n", route, err)
+		s.client.debug(DbgError, "http.NewRequest(GET, %s) error: '%s'\n", route, err)
 		errStr := wski18n.T("Unable to create HTTP request for GET '{{.route}}': {{.err}}",
 			map[string]interface{}{"route": route, "err": err})
 		werr := MakeWskErrorFromWskError(errors.New(errStr), err, EXIT_CODE_ERR_GENERAL, DISPLAY_MSG, NO_DISPLAY_USAGE)
 		return nil, nil, werr
 	}
 
-	Debug(DbgInfo, "Sending HTTP request - URL '%s'; req %#v\n", req.URL.String(), req)
+	s.client.debug(DbgInfo, "Sending HTTP request - URL '%s'\n", s.client.redactedURL(req))
 
 	r := new(Response)
-	resp, err := s.client.Do(req, &r, ExitWithSuccessOnTimeout)
+	resp, err := s.client.DoContext(ctx, req, &r, ExitWithSuccessOnTimeout)
 	if err != nil {
-		Debug(DbgError, "s.client.Do() error - HTTP req %s; error '%s'\n", req.URL.String(), err)
+		s.client.debug(DbgError, "s.client.Do() error - HTTP req %s; error '%s'\n", s.client.redactedURL(req), err)
 		return nil, resp, err
 	}
 
@@ -0,0 +1,101 @@
+// +build unit
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package whisk
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const (
+	ACTIVATION_NOT_FOUND = `{"error":"The requested resource does not exist.","code":"a1b2c3"}`
+	ACTIVATION_RECORD    = `{
+        "namespace": "my_namespace",
+        "name": "test",
+        "version": "0.0.1",
+        "activationId": "f00ba7",
+        "start": 1500000000000,
+        "end": 1500000000100,
+        "duration": 100,
+        "response": {"status": "success", "success": true, "result": {"message": "hello"}},
+        "logs": ["stdout: hello"]
+    }`
+)
+
+// newActivationServer answers the first `missing` activation requests with a 404, then returns the record.
+func newActivationServer(missing int, polls *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*polls++
+		w.Header().Set("Content-Type", "application/json")
+		if *polls <= missing {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(ACTIVATION_NOT_FOUND))
+			return
+		}
+		w.Write([]byte(ACTIVATION_RECORD))
+	}))
+}
+
+func TestActivationWait(t *testing.T) {
+	polls := 0
+	server := newActivationServer(2, &polls)
+	defer server.Close()
+	client := newLocalTestClient(t, server, nil)
+
+	activation, _, err := client.Activations.Wait("f00ba7", &WaitOptions{InitialInterval: time.Millisecond})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, polls)
+	assert.Equal(t, "f00ba7", activation.ActivationID)
+	assert.Equal(t, true, activation.Response.Success)
+}
+
+func TestActivationWaitTimeout(t *testing.T) {
+	polls := 0
+	server := newActivationServer(1000, &polls)
+	defer server.Close()
+	client := newLocalTestClient(t, server, nil)
+
+	_, _, err := client.Activations.Wait("f00ba7", &WaitOptions{
+		Timeout:         50 * time.Millisecond,
+		InitialInterval: 5 * time.Millisecond,
+	})
+	assert.NotNil(t, err)
+	whiskErr, ok := err.(*WskError)
+	assert.True(t, ok)
+	assert.True(t, whiskErr.TimedOut)
+	assert.True(t, polls > 1)
+}
+
+func TestActivationWaitOtherError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error":"The supplied authentication is invalid","code":"d4e5f6"}`))
+	}))
+	defer server.Close()
+	client := newLocalTestClient(t, server, nil)
+
+	_, resp, err := client.Activations.Wait("f00ba7", &WaitOptions{InitialInterval: time.Millisecond})
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
@@ -18,6 +18,7 @@
 package whisk
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/apache/openwhisk-client-go/wski18n"
@@ -315,22 +316,26 @@ func (api ApiFilteredRow) ToSummaryRowString() string {
 }
 
 func (s *ApiService) List(apiListOptions *ApiListRequestOptions) (*ApiListResponse, *http.Response, error) {
+	return s.ListContext(context.Background(), apiListOptions)
+}
+
+func (s *ApiService) ListContext(ctx context.Context, apiListOptions *ApiListRequestOptions) (*ApiListResponse, *http.Response, error) {
 	route := "web/whisk.system/apimgmt/getApi.http"
 
-	routeUrl, err := addRouteOptions(route, apiListOptions)
+	routeUrl, err := addRouteOptions(s.client, route, apiListOptions)
 	if err != nil {
-		Debug(DbgError, "addRouteOptions(%s, %#v) error: '%s'\n", route, apiListOptions, err)
+		s.client.debug(DbgError, "addRouteOptions(%s, %s) error: '%s'\n", route, redactOptions(s.client, apiListOptions), err)
 		errMsg := wski18n.T("Unable to add route options '{{.options}}'",
-			map[string]interface{}{"options": apiListOptions})
+			map[string]interface{}{"options": redactOptions(s.client, apiListOptions)})
 		whiskErr := MakeWskErrorFromWskError(errors.New(errMsg), err, EXIT_CODE_ERR_GENERAL, DISPLAY_MSG,
 			NO_DISPLAY_USAGE)
 		return nil, nil, whiskErr
 	}
-	Debug(DbgInfo, "Api GET/list route with api options: %s\n", routeUrl)
+	s.client.debug(DbgInfo, "Api GET/list route with api options: %s\n", s.client.redactor().url(routeUrl))
 
 	req, err := s.client.NewRequestUrl("GET", routeUrl, nil, DoNotIncludeNamespaceInUrl, AppendOpenWhiskPathPrefix, EncodeBodyAsJson, AuthRequired)
 	if err != nil {
-		Debug(DbgError, "http.NewRequestUrl(GET, %s, nil, DoNotIncludeNamespaceInUrl, AppendOpenWhiskPathPrefix, EncodeBodyAsJson) error: '%s'\n", routeUrl, err)
+		s.client.debug(DbgError, "http.NewRequestUrl(GET, %s, nil, DoNotIncludeNamespaceInUrl, AppendOpenWhiskPathPrefix, EncodeBodyAsJson) error: '%s'\n", s.client.redactor().url(routeUrl), err)
 		errMsg := wski18n.T("Unable to create HTTP request for GET '{{.route}}': {{.err}}",
 			map[string]interface{}{"route": routeUrl, "err": err})
 		whiskErr := MakeWskErrorFromWskError(errors.New(errMsg), err, EXIT_CODE_ERR_NETWORK, DISPLAY_MSG,
@@ -339,15 +344,15 @@ func (s *ApiService) List(apiListOptions *ApiListRequestOptions) (*ApiListRespon
 	}
 
 	apiArray := new(ApiListResponse)
-	resp, err := s.client.Do(req, &apiArray, ExitWithErrorOnTimeout)
+	resp, err := s.client.DoContext(ctx, req, &apiArray, ExitWithErrorOnTimeout)
 	if err != nil {
-		Debug(DbgError, "s.client.Do() error - HTTP req %s; error '%s'\n", req.URL.String(), err)
+		s.client.debug(DbgError, "s.client.Do() error - HTTP req %s; error '%s'\n", s.client.redactedURL(req), err)
 		return nil, resp, err
 	}
 
-	err = validateApiListResponse(apiArray)
+	err = s.validateApiListResponse(apiArray)
 	if err != nil {
-		Debug(DbgError, "Not a valid ApiListResponse object\n")
+		s.client.debug(DbgError, "Not a valid ApiListResponse object\n")
 		return nil, resp, err
 	}
 
@@ -355,23 +360,27 @@ func (s *ApiService) List(apiListOptions *ApiListRequestOptions) (*ApiListRespon
 }
 
 func (s *ApiService) Insert(api *ApiCreateRequest, options *ApiCreateRequestOptions, overwrite bool) (*ApiCreateResponse, *http.Response, error) {
+	return s.InsertContext(context.Background(), api, options, overwrite)
+}
+
+func (s *ApiService) InsertContext(ctx context.Context, api *ApiCreateRequest, options *ApiCreateRequestOptions, overwrite bool) (*ApiCreateResponse, *http.Response, error) {
 	route := "web/whisk.system/apimgmt/createApi.http"
-	Debug(DbgInfo, "Api PUT route: %s\n", route)
+	s.client.debug(DbgInfo, "Api PUT route: %s\n", route)
 
-	routeUrl, err := addRouteOptions(route, options)
+	routeUrl, err := addRouteOptions(s.client, route, options)
 	if err != nil {
-		Debug(DbgError, "addRouteOptions(%s, %#v) error: '%s'\n", route, options, err)
+		s.client.debug(DbgError, "addRouteOptions(%s, %s) error: '%s'\n", route, redactOptions(s.client, options), err)
 		errMsg := wski18n.T("Unable to add route options '{{.options}}'",
-			map[string]interface{}{"options": options})
+			map[string]interface{}{"options": redactOptions(s.client, options)})
 		whiskErr := MakeWskErrorFromWskError(errors.New(errMsg), err, EXIT_CODE_ERR_GENERAL, DISPLAY_MSG,
 			NO_DISPLAY_USAGE)
 		return nil, nil, whiskErr
 	}
-	Debug(DbgError, "Api create route with options: %s\n", routeUrl)
+	s.client.debug(DbgError, "Api create route with options: %s\n", s.client.redactor().url(routeUrl))
 
 	req, err := s.client.NewRequestUrl("POST", routeUrl, api, DoNotIncludeNamespaceInUrl, AppendOpenWhiskPathPrefix, EncodeBodyAsJson, AuthRequired)
 	if err != nil {
-		Debug(DbgError, "http.NewRequestUrl(POST, %s, nil, DoNotIncludeNamespaceInUrl, AppendOpenWhiskPathPrefix, EncodeBodyAsJson) error: '%s'\n", route, err)
+		s.client.debug(DbgError, "http.NewRequestUrl(POST, %s, nil, DoNotIncludeNamespaceInUrl, AppendOpenWhiskPathPrefix, EncodeBodyAsJson) error: '%s'\n", route, err)
 		errMsg := wski18n.T("Unable to create HTTP request for POST '{{.route}}': {{.err}}",
 			map[string]interface{}{"route": route, "err": err})
 		whiskErr := MakeWskErrorFromWskError(errors.New(errMsg), err, EXIT_CODE_ERR_NETWORK, DISPLAY_MSG,
@@ -380,15 +389,15 @@ func (s *ApiService) Insert(api *ApiCreateRequest, options *ApiCreateRequestOpti
 	}
 
 	retApi := new(ApiCreateResponse)
-	resp, err := s.client.Do(req, &retApi, ExitWithErrorOnTimeout)
+	resp, err := s.client.DoContext(ctx, req, &retApi, ExitWithErrorOnTimeout)
 	if err != nil {
-		Debug(DbgError, "s.client.Do() error - HTTP req %s; error '%s'\n", req.URL.String(), err)
+		s.client.debug(DbgError, "s.client.Do() error - HTTP req %s; error '%s'\n", s.client.redactedURL(req), err)
 		return nil, resp, err
 	}
 
-	err = validateApiSwaggerResponse(retApi.Swagger)
+	err = s.validateApiSwaggerResponse(retApi.Swagger)
 	if err != nil {
-		Debug(DbgError, "Not a valid API creation response\n")
+		s.client.debug(DbgError, "Not a valid API creation response\n")
 		return nil, resp, err
 	}
 
@@ -396,23 +405,27 @@ func (s *ApiService) Insert(api *ApiCreateRequest, options *ApiCreateRequestOpti
 }
 
 func (s *ApiService) Get(api *ApiGetRequest, options *ApiGetRequestOptions) (*ApiGetResponse, *http.Response, error) {
+	return s.GetContext(context.Background(), api, options)
+}
+
+func (s *ApiService) GetContext(ctx context.Context, api *ApiGetRequest, options *ApiGetRequestOptions) (*ApiGetResponse, *http.Response, error) {
 	route := "web/whisk.system/apimgmt/getApi.http"
-	Debug(DbgInfo, "Api GET route: %s\n", route)
+	s.client.debug(DbgInfo, "Api GET route: %s\n", route)
 
-	routeUrl, err := addRouteOptions(route, options)
+	routeUrl, err := addRouteOptions(s.client, route, options)
 	if err != nil {
-		Debug(DbgError, "addRouteOptions(%s, %#v) error: '%s'\n", route, options, err)
+		s.client.debug(DbgError, "addRouteOptions(%s, %s) error: '%s'\n", route, redactOptions(s.client, options), err)
 		errMsg := wski18n.T("Unable to add route options '{{.options}}'",
-			map[string]interface{}{"options": options})
+			map[string]interface{}{"options": redactOptions(s.client, options)})
 		whiskErr := MakeWskErrorFromWskError(errors.New(errMsg), err, EXIT_CODE_ERR_GENERAL, DISPLAY_MSG,
 			NO_DISPLAY_USAGE)
 		return nil, nil, whiskErr
 	}
-	Debug(DbgError, "Api get route with options: %s\n", routeUrl)
+	s.client.debug(DbgError, "Api get route with options: %s\n", s.client.redactor().url(routeUrl))
 
 	req, err := s.client.NewRequestUrl("GET", routeUrl, nil, DoNotIncludeNamespaceInUrl, AppendOpenWhiskPathPrefix, EncodeBodyAsJson, AuthRequired)
 	if err != nil {
-		Debug(DbgError, "http.NewRequestUrl(GET, %s, nil, DoNotIncludeNamespaceInUrl, AppendOpenWhiskPathPrefix, EncodeBodyAsJson) error: '%s'\n", route, err)
+		s.client.debug(DbgError, "http.NewRequestUrl(GET, %s, nil, DoNotIncludeNamespaceInUrl, AppendOpenWhiskPathPrefix, EncodeBodyAsJson) error: '%s'\n", route, err)
 		errMsg := wski18n.T("Unable to create HTTP request for GET '{{.route}}': {{.err}}",
 			map[string]interface{}{"route": route, "err": err})
 		whiskErr := MakeWskErrorFromWskError(errors.New(errMsg), err, EXIT_CODE_ERR_NETWORK, DISPLAY_MSG,
@@ -421,9 +434,9 @@ func (s *ApiService) Get(api *ApiGetRequest, options *ApiGetRequestOptions) (*Ap
 	}
 
 	retApi := new(ApiGetResponse)
-	resp, err := s.client.Do(req, &retApi, ExitWithErrorOnTimeout)
+	resp, err := s.client.DoContext(ctx, req, &retApi, ExitWithErrorOnTimeout)
 	if err != nil {
-		Debug(DbgError, "s.client.Do() error - HTTP req %s; error '%s'\n", req.URL.String(), err)
+		s.client.debug(DbgError, "s.client.Do() error - HTTP req %s; error '%s'\n", s.client.redactedURL(req), err)
 		return nil, resp, err
 	}
 
@@ -431,23 +444,27 @@ func (s *ApiService) Get(api *ApiGetRequest, options *ApiGetRequestOptions) (*Ap
 }
 
 func (s *ApiService) Delete(api *ApiDeleteRequest, options *ApiDeleteRequestOptions) (*http.Response, error) {
+	return s.DeleteContext(context.Background(), api, options)
+}
+
+func (s *ApiService) DeleteContext(ctx context.Context, api *ApiDeleteRequest, options *ApiDeleteRequestOptions) (*http.Response, error) {
 	route := "web/whisk.system/apimgmt/deleteApi.http"
-	Debug(DbgInfo, "Api DELETE route: %s\n", route)
+	s.client.debug(DbgInfo, "Api DELETE route: %s\n", route)
 
-	routeUrl, err := addRouteOptions(route, options)
+	routeUrl, err := addRouteOptions(s.client, route, options)
 	if err != nil {
-		Debug(DbgError, "addRouteOptions(%s, %#v) error: '%s'\n", route, options, err)
+		s.client.debug(DbgError, "addRouteOptions(%s, %s) error: '%s'\n", route, redactOptions(s.client, options), err)
 		errMsg := wski18n.T("Unable to add route options '{{.options}}'",
-			map[string]interface{}{"options": options})
+			map[string]interface{}{"options": redactOptions(s.client, options)})
 		whiskErr := MakeWskErrorFromWskError(errors.New(errMsg), err, EXIT_CODE_ERR_GENERAL, DISPLAY_MSG,
 			NO_DISPLAY_USAGE)
 		return nil, whiskErr
 	}
-	Debug(DbgError, "Api DELETE route with options: %s\n", routeUrl)
+	s.client.debug(DbgError, "Api DELETE route with options: %s\n", s.client.redactor().url(routeUrl))
 
 	req, err := s.client.NewRequestUrl("DELETE", routeUrl, nil, DoNotIncludeNamespaceInUrl, AppendOpenWhiskPathPrefix, EncodeBodyAsJson, AuthRequired)
 	if err != nil {
-		Debug(DbgError, "http.NewRequestUrl(DELETE, %s, nil, DoNotIncludeNamespaceInUrl, AppendOpenWhiskPathPrefix, EncodeBodyAsJson) error: '%s'\n", route, err)
+		s.client.debug(DbgError, "http.NewRequestUrl(DELETE, %s, nil, DoNotIncludeNamespaceInUrl, AppendOpenWhiskPathPrefix, EncodeBodyAsJson) error: '%s'\n", route, err)
 		errMsg := wski18n.T("Unable to create HTTP request for DELETE '{{.route}}': {{.err}}",
 			map[string]interface{}{"route": route, "err": err})
 		whiskErr := MakeWskErrorFromWskError(errors.New(errMsg), err, EXIT_CODE_ERR_NETWORK, DISPLAY_MSG,
@@ -456,43 +473,43 @@ func (s *ApiService) Delete(api *ApiDeleteRequest, options *ApiDeleteRequestOpti
 	}
 
 	retApi := new(ApiDeleteResponse)
-	resp, err := s.client.Do(req, &retApi, ExitWithErrorOnTimeout)
+	resp, err := s.client.DoContext(ctx, req, &retApi, ExitWithErrorOnTimeout)
 	if err != nil {
-		Debug(DbgError, "s.client.Do() error - HTTP req %s; error '%s'\n", req.URL.String(), err)
+		s.client.debug(DbgError, "s.client.Do() error - HTTP req %s; error '%s'\n", s.client.redactedURL(req), err)
 		return resp, err
 	}
 
 	return nil, nil
 }
 
-func validateApiListResponse(apiList *ApiListResponse) error {
+func (s *ApiService) validateApiListResponse(apiList *ApiListResponse) error {
 	for i := 0; i < len(apiList.Apis); i++ {
 		if apiList.Apis[i].ApiValue == nil {
-			Debug(DbgError, "validateApiResponse: No value stanza in api %v\n", apiList.Apis[i])
+			s.client.debug(DbgError, "validateApiResponse: No value stanza in api %s\n", apiList.Apis[i].ApiId)
 			errMsg := wski18n.T("Internal error. Missing value stanza in API configuration response")
 			whiskErr := MakeWskError(errors.New(errMsg), EXIT_CODE_ERR_NETWORK, DISPLAY_MSG, NO_DISPLAY_USAGE)
 			return whiskErr
 		}
-		err := validateApiSwaggerResponse(apiList.Apis[i].ApiValue.Swagger)
+		err := s.validateApiSwaggerResponse(apiList.Apis[i].ApiValue.Swagger)
 		if err != nil {
-			Debug(DbgError, "validateApiListResponse: Invalid Api: %v\n", apiList.Apis[i])
+			s.client.debug(DbgError, "validateApiListResponse: Invalid Api: %s\n", apiList.Apis[i].ApiId)
 			return err
 		}
 	}
 	return nil
 }
 
-func validateApiSwaggerResponse(swagger *ApiSwagger) error {
+func (s *ApiService) validateApiSwaggerResponse(swagger *ApiSwagger) error {
 	if swagger == nil {
-		Debug(DbgError, "validateApiSwaggerResponse: No apidoc stanza in api\n")
+		s.client.debug(DbgError, "validateApiSwaggerResponse: No apidoc stanza in api\n")
 		errMsg := wski18n.T("Internal error. Missing apidoc stanza in API configuration")
 		whiskErr := MakeWskError(errors.New(errMsg), EXIT_CODE_ERR_NETWORK, DISPLAY_MSG, NO_DISPLAY_USAGE)
 		return whiskErr
 	}
 	for path := range swagger.Paths {
-		err := validateApiPath(swagger.Paths[path])
+		err := s.validateApiPath(swagger.Paths[path])
 		if err != nil {
-			Debug(DbgError, "validateApiResponse: Invalid Api Path object: %v\n", swagger.Paths[path])
+			s.client.debug(DbgError, "validateApiResponse: Invalid Api Path object: %s\n", path)
 			return err
 		}
 	}
@@ -500,20 +517,20 @@ func validateApiSwaggerResponse(swagger *ApiSwagger) error {
 	return nil
 }
 
-func validateApiPath(path *ApiSwaggerPath) error {
+func (s *ApiService) validateApiPath(path *ApiSwaggerPath) error {
 	for op, opv := range path.MakeOperationMap() {
-		err := validateApiOperation(op, opv)
+		err := s.validateApiOperation(op, opv)
 		if err != nil {
-			Debug(DbgError, "validateApiPath: Invalid Api operation object: %v\n", opv)
+			s.client.debug(DbgError, "validateApiPath: Invalid Api operation object: %s\n", op)
 			return err
 		}
 	}
 	return nil
 }
 
-func validateApiOperation(opName string, op *ApiSwaggerOperation) error {
+func (s *ApiService) validateApiOperation(opName string, op *ApiSwaggerOperation) error {
 	if op.XOpenWhisk != nil && len(op.OperationId) == 0 {
-		Debug(DbgError, "validateApiOperation: No operationId field in operation %v\n", op)
+		s.client.debug(DbgError, "validateApiOperation: No operationId field in operation %s\n", opName)
 		errMsg := wski18n.T("Missing operationId field in API configuration for operation {{.op}}",
 			map[string]interface{}{"op": opName})
 		whiskErr := MakeWskError(errors.New(errMsg), EXIT_CODE_ERR_NETWORK, DISPLAY_MSG, NO_DISPLAY_USAGE)
@@ -521,7 +538,7 @@ func validateApiOperation(opName string, op *ApiSwaggerOperation) error {
 	}
 
 	if op.XOpenWhisk != nil && len(op.XOpenWhisk.Namespace) == 0 {
-		Debug(DbgError, "validateApiOperation: no x-openwhisk.namespace stanza in operation %v\n", op)
+		s.client.debug(DbgError, "validateApiOperation: no x-openwhisk.namespace stanza in operation %s\n", opName)
 		errMsg := wski18n.T("Missing x-openwhisk.namespace field in API configuration for operation {{.op}}",
 			map[string]interface{}{"op": opName})
 		whiskErr := MakeWskError(errors.New(errMsg), EXIT_CODE_ERR_NETWORK, DISPLAY_MSG, NO_DISPLAY_USAGE)
@@ -531,14 +548,14 @@ func validateApiOperation(opName string, op *ApiSwaggerOperation) error {
 	// Note: The op.XOpenWhisk.Package field can have a value of "", so don't enforce a value
 
 	if op.XOpenWhisk != nil && len(op.XOpenWhisk.ActionName) == 0 {
-		Debug(DbgError, "validateApiOperation: no x-openwhisk.action stanza in operation %v\n", op)
+		s.client.debug(DbgError, "validateApiOperation: no x-openwhisk.action stanza in operation %s\n", opName)
 		errMsg := wski18n.T("Missing x-openwhisk.action field in API configuration for operation {{.op}}",
 			map[string]interface{}{"op": opName})
 		whiskErr := MakeWskError(errors.New(errMsg), EXIT_CODE_ERR_NETWORK, DISPLAY_MSG, NO_DISPLAY_USAGE)
 		return whiskErr
 	}
 	if op.XOpenWhisk != nil && len(op.XOpenWhisk.ApiUrl) == 0 {
-		Debug(DbgError, "validateApiOperation: no x-openwhisk.url stanza in operation %v\n", op)
+		s.client.debug(DbgError, "validateApiOperation: no x-openwhisk.url stanza in operation %s\n", opName)
 		errMsg := wski18n.T("Missing x-openwhisk.url field in API configuration for operation {{.op}}",
 			map[string]interface{}{"op": opName})
 		whiskErr := MakeWskError(errors.New(errMsg), EXIT_CODE_ERR_NETWORK, DISPLAY_MSG, NO_DISPLAY_USAGE)
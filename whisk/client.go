@@ -19,6 +19,7 @@ package whisk
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"encoding/base64"
 	"encoding/json"
@@ -26,13 +27,13 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
-	"net"
 	"net/http"
 	"net/url"
 	"reflect"
 	"regexp"
 	"runtime"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/apache/openwhisk-client-go/wski18n"
@@ -60,6 +61,15 @@ type ClientInterface interface {
 	Do(req *http.Request, v interface{}, ExitWithErrorOnTimeout bool, secretToObfuscate ...ObfuscateSet) (*http.Response, error)
 }
 
+// ClientContextInterface is a ClientInterface which sends its requests with a context, such as Client.  The
+// services use DoContext when their client implements it, and otherwise Do with the context set on the request.
+// The URLs and options in their log and error messages are redacted according to RedactionPolicy.
+type ClientContextInterface interface {
+	ClientInterface
+	DoContext(ctx context.Context, req *http.Request, v interface{}, ExitWithErrorOnTimeout bool, secretToObfuscate ...ObfuscateSet) (*http.Response, error)
+	RedactionPolicy() *RedactionPolicy
+}
+
 type TriggerServiceInterface interface {
 	List(options *TriggerListOptions) ([]Trigger, *http.Response, error)
 	Insert(trigger *Trigger, overwrite bool) (*Trigger, *http.Response, error)
@@ -68,10 +78,25 @@ type TriggerServiceInterface interface {
 	Fire(triggerName string, payload interface{}) (*Trigger, *http.Response, error)
 }
 
+// TriggerServiceContextInterface is a TriggerServiceInterface with the context-aware and paging methods of
+// TriggerService, e.g. client.Triggers.(TriggerServiceContextInterface).GetContext(ctx, "hello").
+type TriggerServiceContextInterface interface {
+	TriggerServiceInterface
+	ListContext(ctx context.Context, options *TriggerListOptions) ([]Trigger, *http.Response, error)
+	InsertContext(ctx context.Context, trigger *Trigger, overwrite bool) (*Trigger, *http.Response, error)
+	GetContext(ctx context.Context, triggerName string) (*Trigger, *http.Response, error)
+	DeleteContext(ctx context.Context, triggerName string) (*Trigger, *http.Response, error)
+	FireContext(ctx context.Context, triggerName string, payload interface{}) (*Trigger, *http.Response, error)
+	ListAll(options *TriggerListOptions) *TriggerPager
+	ListAllContext(ctx context.Context, options *TriggerListOptions) *TriggerPager
+}
+
+// Client is safe for concurrent use by multiple goroutines.  Its configuration is a copy of the one given to
+// NewClient, which must not be modified once the client is in use; use WithNamespace to target another namespace.
 type Client struct {
 	client *http.Client
 	*Config
-	Transport *http.Transport
+	Transport *http.Transport // Transport of the http client, with the TLS configuration; nil for http.DefaultTransport or a custom http.RoundTripper without TLS options
 
 	Sdks        *SdkService
 	Triggers    TriggerServiceInterface
@@ -82,6 +107,11 @@ type Client struct {
 	Namespaces  *NamespaceService
 	Info        *InfoService
 	Apis        *ApiService
+
+	middlewareLock sync.RWMutex
+	middleware     []Middleware
+	limiter        *rateLimiter // State of Config.RateLimit, shared with the copies of the client
+	namespaced     bool         // Whether the namespace was set with WithNamespace, which the activations honor too
 }
 
 type Config struct {
@@ -92,13 +122,24 @@ type Config struct {
 	Host              string
 	BaseURL           *url.URL // NOTE :: Default is "openwhisk.ng.bluemix.net"
 	Version           string
-	Verbose           bool
-	Debug             bool // For detailed tracing
+	Verbose           bool // Verbose output of this client only; SetVerbose enables it for every client
+	Debug             bool // For detailed tracing of this client only; SetDebug enables it for every client
 	Insecure          bool
 	UserAgent         string
 	ApigwAccessToken  string
 	ApigwTenantId     string
 	AdditionalHeaders http.Header
+	Retry             *RetryPolicy       // Optional; when nil every request is attempted once
+	Logger            Logger             // Optional; when nil requests are traced to stdout, as enabled by Verbose and Debug
+	Credentials       CredentialProvider // Optional; when nil requests are authenticated with AuthToken
+	RateLimit         *RateLimitPolicy   // Optional; when nil requests are not limited on the client side
+	CertPEM           []byte             // Client certificate as PEM data, instead of the Cert file
+	KeyPEM            []byte             // Client key as PEM data, instead of the Key file
+	CACert            string             // File of PEM CA certificates trusted instead of the system ones
+	CACertPEM         []byte             // PEM CA certificates trusted instead of the system ones, with those of CACert
+	MinTLSVersion     uint16             // Minimum TLS version, e.g. tls.VersionTLS12; default is the crypto/tls one
+	Redaction         *RedactionPolicy   // Optional; secrets redacted from the output in addition to DefaultRedactionPolicy
+	HAR               *HARRecorder       // Optional; when set the HTTP traffic is recorded as a HAR log
 }
 
 type ObfuscateSet struct {
@@ -113,11 +154,15 @@ var DefaultObfuscateArr = []ObfuscateSet{
 	},
 }
 
-// NewClient creates a new whisk client with the provided http client and whisk configuration.
+// NewClient creates a new whisk client with the provided http client and whisk configuration.  The defaults of
+// the BaseURL, Namespace, Version and UserAgent are filled in the provided configuration, which the client copies.
 //
-// A new http.Transport will be created when client cert or TLS insecure options are set.
-// If one use custom transport and want to keep it intact, please opt out TLS related fields
-// in configInput and construct TLS configuration in the custom transport.
+// When client cert, CA cert, minimum TLS version or TLS insecure options are set, the TLS configuration
+// is applied to a copy of the http.Transport of the http client, used by a copy of the http client; the
+// provided http client and transport are left untouched, and the settings of the transport, including its
+// own TLS configuration, are kept.  A custom http.RoundTripper implementing RoundTripperWrapper is copied
+// with the TLS configuration applied to its base transport; NewClient returns an error for the other
+// RoundTrippers, which cannot be configured.
 func NewClient(httpClient *http.Client, configInput *Config) (*Client, error) {
 
 	var config *Config
@@ -145,7 +190,7 @@ func NewClient(httpClient *http.Client, configInput *Config) (*Client, error) {
 	} else if config.BaseURL == nil {
 		config.BaseURL, err = GetUrlBase(config.Host)
 		if err != nil {
-			Debug(DbgError, "Unable to create request URL, because the api host %s is invalid: %s\n", config.Host, err)
+			(&Client{Config: config}).debug(DbgError, "Unable to create request URL, because the api host %s is invalid: %s\n", config.Host, err)
 			errStr = wski18n.T("Unable to create request URL, because the api host '{{.host}}' is invalid: {{.err}}",
 				map[string]interface{}{"host": config.Host, "err": err})
 		}
@@ -168,11 +213,33 @@ func NewClient(httpClient *http.Client, configInput *Config) (*Client, error) {
 		config.UserAgent = "OpenWhisk-Go-Client " + runtime.GOOS + " " + runtime.GOARCH
 	}
 
+	// The defaults are filled in the caller's configuration, as they always were, but the client works on a copy of
+	// it, so that the caller may reuse or modify its own afterwards
+	configCopy := *config
+	if config.BaseURL != nil {
+		baseURL := *config.BaseURL
+		configCopy.BaseURL = &baseURL
+	}
+	configCopy.AdditionalHeaders = config.AdditionalHeaders.Clone()
+	config = &configCopy
+
 	c := &Client{
-		client: httpClient,
-		Config: config,
+		client:  httpClient,
+		Config:  config,
+		limiter: newRateLimiter(config.RateLimit),
+	}
+
+	c.initServices()
+
+	werr := c.LoadX509KeyPair()
+	if werr != nil {
+		return nil, werr
 	}
 
+	return c, nil
+}
+
+func (c *Client) initServices() {
 	c.Sdks = &SdkService{client: c}
 	c.Triggers = &TriggerService{client: c}
 	c.Actions = &ActionService{client: c}
@@ -182,72 +249,60 @@ func NewClient(httpClient *http.Client, configInput *Config) (*Client, error) {
 	c.Namespaces = &NamespaceService{client: c}
 	c.Info = &InfoService{client: c}
 	c.Apis = &ApiService{client: c}
+}
 
-	werr := c.LoadX509KeyPair()
-	if werr != nil {
-		return nil, werr
+// WithNamespace returns a copy of the client whose requests target the given namespace; an empty namespace is
+// the default namespace of the credentials, "_".  The copy shares the http client and the configuration of c,
+// except for the namespace, and starts with the middleware of c.  c itself is not modified.
+//
+// The activations of the copy are in the given namespace too, while the ones of a client created with NewClient
+// are always in "_", whatever its Config.Namespace.
+func (c *Client) WithNamespace(namespace string) *Client {
+	if len(namespace) == 0 {
+		namespace = "_"
 	}
-
-	return c, nil
+	config := *c.Config
+	config.Namespace = namespace
+
+	clone := &Client{
+		client:     c.client,
+		Config:     &config,
+		Transport:  c.Transport,
+		limiter:    c.limiter,
+		namespaced: true,
+	}
+	c.middlewareLock.RLock()
+	clone.middleware = append([]Middleware(nil), c.middleware...)
+	c.middlewareLock.RUnlock()
+	clone.initServices()
+	return clone
 }
 
 func (c *Client) LoadX509KeyPair() error {
-	tlsConfig := &tls.Config{
-		InsecureSkipVerify: c.Config.Insecure,
+	tlsConfig, werr := c.newTLSConfig()
+	if werr != nil {
+		return werr
 	}
 
-	if c.Config.Cert != "" && c.Config.Key != "" {
-		if cert, err := ReadX509KeyPair(c.Config.Cert, c.Config.Key); err == nil {
-			tlsConfig.Certificates = []tls.Certificate{cert}
+	transport, _ := c.client.Transport.(*http.Transport)
+
+	// Only change the transport when a custom TLS configuration is needed
+	if tlsConfig != nil {
+		if roundTripper, tlsTransport, ok := wrapTLSConfig(c.client.Transport, tlsConfig); ok {
+			// Change the transport of a copy of the http client, leaving the provided one untouched
+			httpClient := *c.client
+			httpClient.Transport = roundTripper
+			c.client = &httpClient
+			transport = tlsTransport
 		} else {
-			errStr := wski18n.T("Unable to load the X509 key pair due to the following reason: {{.err}}",
-				map[string]interface{}{"err": err})
+			errStr := wski18n.T("The TLS configuration cannot be applied to the http.RoundTripper of the http client, which is neither an http.Transport nor a RoundTripperWrapper")
+			c.debug(DbgError, "%s\n", errStr)
 			werr := MakeWskError(errors.New(errStr), EXIT_CODE_ERR_GENERAL, DISPLAY_MSG, NO_DISPLAY_USAGE)
 			return werr
 		}
-	} else if !c.Config.Insecure {
-		if c.Config.Cert == "" {
-			warningStr := "The Cert file is not configured. Please configure the missing Cert file, if there is a security issue accessing the service.\n"
-			Debug(DbgWarn, warningStr)
-			if c.Config.Key != "" {
-				errStr := wski18n.T("The Cert file is not configured. Please configure the missing Cert file.\n")
-				werr := MakeWskError(errors.New(errStr), EXIT_CODE_ERR_GENERAL, DISPLAY_MSG, NO_DISPLAY_USAGE)
-				return werr
-			}
-		}
-		if c.Config.Key == "" {
-			warningStr := "The Key file is not configured. Please configure the missing Key file, if there is a security issue accessing the service.\n"
-			Debug(DbgWarn, warningStr)
-			if c.Config.Cert != "" {
-				errStr := wski18n.T("The Key file is not configured. Please configure the missing Key file.\n")
-				werr := MakeWskError(errors.New(errStr), EXIT_CODE_ERR_GENERAL, DISPLAY_MSG, NO_DISPLAY_USAGE)
-				return werr
-			}
-		}
-	}
-
-	// Only replace the existing transport when a custom TLS configuration is needed
-	if tlsConfig.InsecureSkipVerify || tlsConfig.Certificates != nil {
-		if c.client.Transport != nil {
-			warningStr := "The provided http.Transport is replaced to match the TLS configuration. Custom transport cannot coexist with nondefault TLS configuration"
-			Debug(DbgWarn, warningStr)
-		}
-		// Use the defaultTransport as the transport basis to maintain proxy support
-		c.client.Transport = &http.Transport{
-			Proxy: http.ProxyFromEnvironment,
-			DialContext: (&net.Dialer{
-				Timeout:   30 * time.Second,
-				KeepAlive: 30 * time.Second,
-				DualStack: true,
-			}).DialContext,
-			MaxIdleConns:          100,
-			IdleConnTimeout:       90 * time.Second,
-			TLSHandshakeTimeout:   10 * time.Second,
-			ExpectContinueTimeout: 1 * time.Second,
-			TLSClientConfig:       tlsConfig,
-		}
 	}
 
+	c.Transport = transport
 	return nil
 }
 
@@ -273,7 +328,7 @@ func (c *Client) NewRequest(method, urlStr string, body interface{}, includeName
 	urlStr = fmt.Sprintf("%s/%s", c.BaseURL.String(), urlStr)
 	u, err := url.Parse(urlStr)
 	if err != nil {
-		Debug(DbgError, "url.Parse(%s) error: %s\n", urlStr, err)
+		c.debug(DbgError, "url.Parse(%s) error: %s\n", urlStr, err)
 		errStr := wski18n.T("Invalid request URL '{{.url}}': {{.err}}",
 			map[string]interface{}{"url": urlStr, "err": err})
 		werr := MakeWskError(errors.New(errStr), EXIT_CODE_ERR_GENERAL, DISPLAY_MSG, NO_DISPLAY_USAGE)
@@ -288,7 +343,7 @@ func (c *Client) NewRequest(method, urlStr string, body interface{}, includeName
 		err := encoder.Encode(body)
 
 		if err != nil {
-			Debug(DbgError, "json.Encode(%#v) error: %s\n", body, err)
+			c.debug(DbgError, "json.Encode(%#v) error: %s\n", body, err)
 			errStr := wski18n.T("Error encoding request body: {{.err}}", map[string]interface{}{"err": err})
 			werr := MakeWskError(errors.New(errStr), EXIT_CODE_ERR_GENERAL, DISPLAY_MSG, NO_DISPLAY_USAGE)
 			return nil, werr
@@ -297,7 +352,7 @@ func (c *Client) NewRequest(method, urlStr string, body interface{}, includeName
 
 	req, err := http.NewRequest(method, u.String(), buf)
 	if err != nil {
-		Debug(DbgError, "http.NewRequest(%v, %s, buf) error: %s\n", method, u.String(), err)
+		c.debug(DbgError, "http.NewRequest(%v, %s, buf) error: %s\n", method, u.String(), err)
 		errStr := wski18n.T("Error initializing request: {{.err}}", map[string]interface{}{"err": err})
 		werr := MakeWskError(errors.New(errStr), EXIT_CODE_ERR_GENERAL, DISPLAY_MSG, NO_DISPLAY_USAGE)
 		return nil, werr
@@ -308,7 +363,7 @@ func (c *Client) NewRequest(method, urlStr string, body interface{}, includeName
 
 	err = c.addAuthHeader(req, AuthRequired)
 	if err != nil {
-		Debug(DbgError, "addAuthHeader() error: %s\n", err)
+		c.debug(DbgError, "addAuthHeader() error: %s\n", err)
 		errStr := wski18n.T("Unable to add the HTTP authentication header: {{.err}}",
 			map[string]interface{}{"err": err})
 		werr := MakeWskErrorFromWskError(errors.New(errStr), err, EXIT_CODE_ERR_GENERAL, DISPLAY_MSG, NO_DISPLAY_USAGE)
@@ -328,14 +383,19 @@ func (c *Client) addAuthHeader(req *http.Request, authRequired bool) error {
 	// Allow for authorization override via Additional Headers
 	authHeaderValue := c.Config.AdditionalHeaders.Get("Authorization")
 	if authHeaderValue != "" {
-		Debug(DbgInfo, "Using additional header authorization\n")
+		c.debug(DbgInfo, "Using additional header authorization\n")
+	} else if c.Config.Credentials != nil {
+		// The credentials are only fetched by authorize, with the context of the call; an Authorization header
+		// without value marks the request to authorize
+		req.Header["Authorization"] = []string{}
+		c.debug(DbgInfo, "Adding auth header; using the credential provider when the request is done\n")
 	} else if c.Config.AuthToken != "" {
 		encodedAuthToken := base64.StdEncoding.EncodeToString([]byte(c.Config.AuthToken))
 		req.Header.Add("Authorization", fmt.Sprintf("Basic %s", encodedAuthToken))
-		Debug(DbgInfo, "Adding basic auth header; using authkey\n")
+		c.debug(DbgInfo, "Adding basic auth header; using authkey\n")
 	} else {
 		if authRequired {
-			Debug(DbgError, "The required authorization key is not configured - neither set as a property nor set via the --auth CLI argument\n")
+			c.debug(DbgError, "The required authorization key is not configured - neither set as a property nor set via the --auth CLI argument\n")
 			errStr := wski18n.T("Authorization key is not configured (--auth is required)")
 			werr := MakeWskError(errors.New(errStr), EXIT_CODE_ERR_USAGE, DISPLAY_MSG, DISPLAY_USAGE)
 			return werr
@@ -347,11 +407,16 @@ func (c *Client) addAuthHeader(req *http.Request, authRequired bool) error {
 // bodyTruncator limits the size of Req/Resp Body for --verbose ONLY.
 // It returns truncated Req/Resp Body, reloaded io.ReadCloser and any errors.
 func BodyTruncator(body io.ReadCloser) (string, io.ReadCloser, error) {
+	return truncateBody(nil, body)
+}
+
+// truncateBody is BodyTruncator tracing to the output of the client c, which may be nil.
+func truncateBody(c *Client, body io.ReadCloser) (string, io.ReadCloser, error) {
 	limit := 1000 // 1000 byte limit, anything over is truncated
 
 	data, err := ioutil.ReadAll(body)
 	if err != nil {
-		Verbose("ioutil.ReadAll(req.Body) error: %s\n", err)
+		c.verbose("ioutil.ReadAll(req.Body) error: %s\n", err)
 		werr := MakeWskError(err, EXIT_CODE_ERR_NETWORK, DISPLAY_MSG, NO_DISPLAY_USAGE)
 		return "", body, werr
 	}
@@ -359,7 +424,7 @@ func BodyTruncator(body io.ReadCloser) (string, io.ReadCloser, error) {
 	reload := ioutil.NopCloser(bytes.NewBuffer(data))
 
 	if len(data) > limit {
-		Verbose("Body exceeds %d bytes and will be truncated\n", limit)
+		c.verbose("Body exceeds %d bytes and will be truncated\n", limit)
 		newData := string(data)[:limit] + "..."
 		return string(newData), reload, nil
 	}
@@ -373,25 +438,104 @@ func BodyTruncator(body io.ReadCloser) (string, io.ReadCloser, error) {
 // interface, the raw response body will be written to v, without attempting to
 // first decode it.
 func (c *Client) Do(req *http.Request, v interface{}, ExitWithErrorOnTimeout bool, secretToObfuscate ...ObfuscateSet) (*http.Response, error) {
+	return c.DoContext(req.Context(), req, v, ExitWithErrorOnTimeout, secretToObfuscate...)
+}
+
+// DoContext is like Do, but the request is sent with the given context so that
+// cancellation and deadlines propagate into the HTTP call.  Every service method
+// has a matching *Context variant (e.g. ActionService.InvokeContext) built on it.
+// The call goes through the middleware registered with Use, once the request is authorized with the
+// credentials of Config.Credentials, if any.
+func (c *Client) DoContext(ctx context.Context, req *http.Request, v interface{}, ExitWithErrorOnTimeout bool, secretToObfuscate ...ObfuscateSet) (*http.Response, error) {
+	req, err := c.authorize(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	do := func(ctx context.Context, req *http.Request, v interface{}) (*http.Response, error) {
+		return c.do(ctx, req, v, ExitWithErrorOnTimeout, secretToObfuscate...)
+	}
+	return c.chain(do)(ctx, req, v)
+}
+
+// doContext sends the request with the context through the client, with DoContext when the client implements
+// ClientContextInterface.
+func doContext(client ClientInterface, ctx context.Context, req *http.Request, v interface{}, ExitWithErrorOnTimeout bool, secretToObfuscate ...ObfuscateSet) (*http.Response, error) {
+	if contextClient, ok := client.(ClientContextInterface); ok {
+		return contextClient.DoContext(ctx, req, v, ExitWithErrorOnTimeout, secretToObfuscate...)
+	}
+	return client.Do(req.WithContext(ctx), v, ExitWithErrorOnTimeout, secretToObfuscate...)
+}
+
+func (c *Client) do(ctx context.Context, req *http.Request, v interface{}, ExitWithErrorOnTimeout bool, secretToObfuscate ...ObfuscateSet) (*http.Response, error) {
+	req = withCorrelationId(req.WithContext(ctx))
+	md := &CallMetadata{CorrelationId: correlationId(ctx), Method: req.Method, URL: c.redactedURL(req)}
+
+	start := time.Now()
+	resp, err := c.doCall(req, v, ExitWithErrorOnTimeout, md, secretToObfuscate)
+	md.Duration = time.Since(start)
+	md.complete(ctx, resp, err)
+	return resp, err
+}
+
+// doCall sends the request and handles its response for do, and sets the number of retries of the metadata.
+func (c *Client) doCall(req *http.Request, v interface{}, ExitWithErrorOnTimeout bool, md *CallMetadata, secretToObfuscate []ObfuscateSet) (*http.Response, error) {
 	var err error
 	var data []byte
-	secrets := append(DefaultObfuscateArr, secretToObfuscate...)
+	// Copy DefaultObfuscateArr, whose spare capacity, if any, would be shared by the concurrent requests
+	secrets := append(append([]ObfuscateSet{}, DefaultObfuscateArr...), secretToObfuscate...)
 
-	req, err = PrintRequestInfo(req, secrets...)
+	if c.Config.Logger == nil {
+		req, err = printRequestInfo(c, req, c.redactor(), secrets)
+	} else {
+		c.logRequest(req, secrets)
+	}
 	//Putting this based on previous code
 	if err != nil {
 		return nil, err
 	}
+	if c.isDebug() {
+		if curl, curlErr := c.curlCommand(req, c.redactor(), c.Config.Insecure); curlErr == nil {
+			if c.Config.Logger != nil {
+				c.log(LogDebug, "Equivalent curl command", LogFields{"method": req.Method, "url": c.redactedURL(req), "curl": curl})
+			} else {
+				c.debug(DbgInfo, "Equivalent curl command:\n%s\n", curl)
+			}
+		}
+	}
 
-	// Issue the request to the Whisk server endpoint
-	resp, err := c.client.Do(req)
+	// Issue the request to the Whisk server endpoint, retrying transient failures per the retry policy
+	start := time.Now()
+	var timer *harTimer
+	var reqBody []byte
+	if c.Config.HAR != nil {
+		timer = newHARTimer()
+		reqBody = readRequestBody(req)
+		req = timer.withTrace(req)
+	}
+	resp, retries, err := c.sendRequest(req)
+	md.Retries = retries
 	if err != nil {
-		Debug(DbgError, "HTTP Do() [req %s] error: %s\n", req.URL.String(), err)
+		c.debug(DbgError, "HTTP Do() [req %s] error: %s\n", c.redactedURL(req), err)
+		c.log(LogError, "HTTP request failed", LogFields{"method": req.Method, "url": c.redactedURL(req),
+			"duration": time.Since(start), "error": err})
+		if timer != nil {
+			c.recordHAR(timer, req, reqBody, nil, nil, 0, secrets, err)
+		}
 		werr := MakeWskError(err, EXIT_CODE_ERR_NETWORK, DISPLAY_MSG, NO_DISPLAY_USAGE)
 		return nil, werr
 	}
 
-	resp, data, err = PrintResponseInfo(resp, secrets...)
+	if timer != nil {
+		timer.responded()
+	}
+	if c.Config.Logger == nil {
+		resp, data, err = printResponseInfo(c, resp, c.redactor(), secrets)
+	} else {
+		resp, data, err = c.logResponse(req, resp, time.Since(start), secrets)
+	}
+	if timer != nil {
+		c.recordHAR(timer, req, reqBody, resp, data, len(data), secrets, err)
+	}
 	if err != nil {
 		return resp, err
 	}
@@ -410,8 +554,8 @@ func (c *Client) Do(req *http.Request, v interface{}, ExitWithErrorOnTimeout boo
 	// Handle 4. HTTP Failure + No body
 	// If this happens, just return no data and an error
 	if !IsHttpRespSuccess(resp) && data == nil {
-		Debug(DbgError, "HTTP failure %d + no body\n", resp.StatusCode)
-		werr := MakeWskError(errors.New(wski18n.T("Command failed due to an HTTP failure")), resp.StatusCode-256,
+		c.debug(DbgError, "HTTP failure %d + no body\n", resp.StatusCode)
+		werr := makeHttpWskError(resp, errors.New(wski18n.T("Command failed due to an HTTP failure")),
 			DISPLAY_MSG, NO_DISPLAY_USAGE)
 		return resp, werr
 	}
@@ -419,7 +563,7 @@ func (c *Client) Do(req *http.Request, v interface{}, ExitWithErrorOnTimeout boo
 	// Handle 5. HTTP Failure + Body matching error format expectation, or body matching a whisk.error() response
 	// Handle 6. HTTP Failure + Body NOT matching error format expectation
 	if !IsHttpRespSuccess(resp) && data != nil {
-		return parseErrorResponse(resp, data, v)
+		return c.parseErrorResponse(resp, data, v)
 	}
 
 	// Handle 0. HTTP Success + Body indicating a whisk failure result
@@ -432,14 +576,14 @@ func (c *Client) Do(req *http.Request, v interface{}, ExitWithErrorOnTimeout boo
 		v != nil &&
 		!strings.Contains(reflect.TypeOf(v).String(), "Activation") && // Request is not `wsk activation get`
 		!(req.URL.Query().Get("result") == "true") && // Request is not `wsk action invoke NNN --result`
-		!IsResponseResultSuccess(data) { // HTTP response body has Whisk error result
-		Debug(DbgInfo, "Got successful HTTP; but activation response reports an error\n")
-		return parseErrorResponse(resp, data, v)
+		!c.isResponseResultSuccess(data) { // HTTP response body has Whisk error result
+		c.debug(DbgInfo, "Got successful HTTP; but activation response reports an error\n")
+		return c.parseErrorResponse(resp, data, v)
 	}
 
 	// Handle 2. HTTP Success + No body expected
 	if IsHttpRespSuccess(resp) && v == nil {
-		Debug(DbgInfo, "No interface provided; no HTTP response body expected\n")
+		c.debug(DbgInfo, "No interface provided; no HTTP response body expected\n")
 		return resp, nil
 	}
 
@@ -455,7 +599,7 @@ func (c *Client) Do(req *http.Request, v interface{}, ExitWithErrorOnTimeout boo
 				NO_MSG_DISPLAYED, NO_DISPLAY_PREFIX, NO_APPLICATION_ERR, TIMED_OUT)
 		}
 
-		return parseSuccessResponse(resp, data, v), err
+		return c.parseSuccessResponse(resp, data, v), err
 	}
 
 	// We should never get here, but just in case return failure to keep the compiler happy
@@ -464,16 +608,23 @@ func (c *Client) Do(req *http.Request, v interface{}, ExitWithErrorOnTimeout boo
 	return resp, werr
 }
 
+// PrintRequestInfo prints the request when verbose output is enabled, with the secrets of DefaultRedactionPolicy
+// redacted and the given regular expressions replaced in the body.
 func PrintRequestInfo(req *http.Request, secretToObfuscate ...ObfuscateSet) (*http.Request, error) {
+	return printRequestInfo(nil, req, newRedactor(nil), secretToObfuscate)
+}
+
+// printRequestInfo is PrintRequestInfo for the verbose output of the client c, which may be nil.
+func printRequestInfo(c *Client, req *http.Request, r *redactor, secretToObfuscate []ObfuscateSet) (*http.Request, error) {
 	var truncatedBody string
 	var err error
-	if IsVerbose() {
+	if c.isVerbose() {
 		fmt.Println("REQUEST:")
-		fmt.Printf("[%s]\t%s\n", req.Method, req.URL)
+		fmt.Printf("[%s]\t%s\n", req.Method, r.url(req.URL))
 
 		if len(req.Header) > 0 {
 			fmt.Println("Req Headers")
-			PrintJSON(req.Header)
+			PrintJSON(r.header(req.Header))
 		}
 
 		if req.Body != nil {
@@ -481,39 +632,48 @@ func PrintRequestInfo(req *http.Request, secretToObfuscate ...ObfuscateSet) (*ht
 			// Since we're emptying out the reader, which is the req.Body, we have to reset it,
 			// but create some copies for our debug messages.
 			buffer, _ := ioutil.ReadAll(req.Body)
-			obfuscatedRequest := ObfuscateText(string(buffer), secretToObfuscate)
+			// Redact the whole body, before it is truncated: a truncated JSON body cannot be parsed
+			obfuscatedRequest := r.body(buffer, secretToObfuscate)
 			req.Body = ioutil.NopCloser(bytes.NewBuffer(buffer))
 
-			if !IsDebug() {
-				if truncatedBody, req.Body, err = BodyTruncator(ioutil.NopCloser(bytes.NewBuffer(buffer))); err != nil {
+			if !c.isDebug() {
+				if truncatedBody, _, err = truncateBody(c, ioutil.NopCloser(strings.NewReader(obfuscatedRequest))); err != nil {
 					return nil, err
 				}
-				fmt.Println(ObfuscateText(truncatedBody, secretToObfuscate))
+				fmt.Println(truncatedBody)
 			} else {
 				fmt.Println(obfuscatedRequest)
 			}
-			Debug(DbgInfo, "Req Body (ASCII quoted string):\n%+q\n", obfuscatedRequest)
+			c.debug(DbgInfo, "Req Body (ASCII quoted string):\n%+q\n", obfuscatedRequest)
 		}
 	}
 	return req, nil
 }
 
+// PrintResponseInfo reads the response body, and prints the response when verbose output is enabled, with the
+// secrets of DefaultRedactionPolicy redacted and the given regular expressions replaced in the body.
 func PrintResponseInfo(resp *http.Response, secretToObfuscate ...ObfuscateSet) (*http.Response, []byte, error) {
+	return printResponseInfo(nil, resp, newRedactor(nil), secretToObfuscate)
+}
+
+// printResponseInfo is PrintResponseInfo for the verbose output of the client c, which may be nil.
+func printResponseInfo(c *Client, resp *http.Response, r *redactor, secretToObfuscate []ObfuscateSet) (*http.Response, []byte, error) {
 	var truncatedBody string
 	// Don't "defer resp.Body.Close()" here because the body is reloaded to allow caller to
 	// do custom body parsing, such as handling per-route error responses.
-	Verbose("RESPONSE:")
-	Verbose("Got response with code %d\n", resp.StatusCode)
+	c.verbose("RESPONSE:")
+	c.verbose("Got response with code %d\n", resp.StatusCode)
 
-	if IsVerbose() && len(resp.Header) > 0 {
+	if c.isVerbose() && len(resp.Header) > 0 {
 		fmt.Println("Resp Headers")
-		PrintJSON(resp.Header)
+		PrintJSON(r.header(resp.Header))
 	}
 
 	// Read the response body
 	data, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
 	if err != nil {
-		Debug(DbgError, "ioutil.ReadAll(resp.Body) error: %s\n", err)
+		c.debug(DbgError, "ioutil.ReadAll(resp.Body) error: %s\n", err)
 		werr := MakeWskError(err, EXIT_CODE_ERR_NETWORK, DISPLAY_MSG, NO_DISPLAY_USAGE)
 		resp.Body = ioutil.NopCloser(bytes.NewBuffer(data))
 		return resp, data, werr
@@ -523,17 +683,20 @@ func PrintResponseInfo(resp *http.Response, secretToObfuscate ...ObfuscateSet) (
 	// the caller will have any empty body to read
 	resp.Body = ioutil.NopCloser(bytes.NewBuffer(data))
 
-	Verbose("Response body size is %d bytes\n", len(data))
+	c.verbose("Response body size is %d bytes\n", len(data))
 
-	if !IsDebug() {
-		if truncatedBody, resp.Body, err = BodyTruncator(ioutil.NopCloser(bytes.NewBuffer(data))); err != nil {
+	if !c.isVerbose() {
+		return resp, data, err
+	}
+	obfuscatedResponse := r.body(data, secretToObfuscate)
+	if !c.isDebug() {
+		if truncatedBody, _, err = truncateBody(c, ioutil.NopCloser(strings.NewReader(obfuscatedResponse))); err != nil {
 			return nil, data, err
 		}
-		Verbose("Response body received:\n%s\n", ObfuscateText(truncatedBody, secretToObfuscate))
+		c.verbose("Response body received:\n%s\n", truncatedBody)
 	} else {
-		obfuscatedResponse := ObfuscateText(string(data), secretToObfuscate)
-		Verbose("Response body received:\n%s\n", obfuscatedResponse)
-		Debug(DbgInfo, "Response body received (ASCII quoted string):\n%+q\n", obfuscatedResponse)
+		c.verbose("Response body received:\n%s\n", obfuscatedResponse)
+		c.debug(DbgInfo, "Response body received (ASCII quoted string):\n%+q\n", obfuscatedResponse)
 	}
 	return resp, data, err
 }
@@ -547,8 +710,8 @@ func ObfuscateText(text string, replacements []ObfuscateSet) string {
 	return obfuscated
 }
 
-func parseErrorResponse(resp *http.Response, data []byte, v interface{}) (*http.Response, error) {
-	Debug(DbgInfo, "HTTP failure %d + body\n", resp.StatusCode)
+func (c *Client) parseErrorResponse(resp *http.Response, data []byte, v interface{}) (*http.Response, error) {
+	c.debug(DbgInfo, "HTTP failure %d + body\n", resp.StatusCode)
 
 	// Determine if an application error was received (#5)
 	buf := bytes.NewBuffer(data)
@@ -561,41 +724,41 @@ func parseErrorResponse(resp *http.Response, data []byte, v interface{}) (*http.
 	// Determine if error is an application error or an error generated by API
 	if err == nil {
 		if errorResponse.Code == nil /*&& errorResponse.ErrMsg != nil */ && resp.StatusCode == 502 {
-			return parseApplicationError(resp, data, v)
+			return c.parseApplicationError(resp, data, v)
 		} else if errorResponse.Code != nil && errorResponse.ErrMsg != nil {
-			Debug(DbgInfo, "HTTP failure %d; server error %s\n", resp.StatusCode, errorResponse)
-			werr := MakeWskError(errorResponse, resp.StatusCode-256, DISPLAY_MSG, NO_DISPLAY_USAGE)
+			c.debug(DbgInfo, "HTTP failure %d; server error %s\n", resp.StatusCode, errorResponse)
+			werr := makeHttpWskError(resp, errorResponse, DISPLAY_MSG, NO_DISPLAY_USAGE)
 			return resp, werr
 		}
 	}
 
 	// Body contents are unknown (#6)
-	Debug(DbgError, "HTTP response with unexpected body failed due to contents parsing error: '%v'\n", err)
+	c.debug(DbgError, "HTTP response with unexpected body failed due to contents parsing error: '%v'\n", err)
 	errMsg := wski18n.T("The connection failed, or timed out. (HTTP status code {{.code}})",
 		map[string]interface{}{"code": resp.StatusCode})
-	whiskErr := MakeWskError(errors.New(errMsg), resp.StatusCode-256, DISPLAY_MSG, NO_DISPLAY_USAGE)
+	whiskErr := makeHttpWskError(resp, errors.New(errMsg), DISPLAY_MSG, NO_DISPLAY_USAGE)
 	return resp, whiskErr
 }
 
-func parseApplicationError(resp *http.Response, data []byte, v interface{}) (*http.Response, error) {
-	Debug(DbgInfo, "Parsing application error\n")
+func (c *Client) parseApplicationError(resp *http.Response, data []byte, v interface{}) (*http.Response, error) {
+	c.debug(DbgInfo, "Parsing application error\n")
 
 	whiskErrorResponse := &WhiskErrorResponse{}
 	err := json.Unmarshal(data, whiskErrorResponse)
 
 	// Handle application errors that occur when --result option is false (#5)
 	if err == nil && whiskErrorResponse != nil && whiskErrorResponse.Response != nil && whiskErrorResponse.Response.Status != nil {
-		Debug(DbgInfo, "Detected response status `%s` that a whisk.error(\"%#v\") was returned\n",
+		c.debug(DbgInfo, "Detected response status `%s` that a whisk.error(\"%#v\") was returned\n",
 			*whiskErrorResponse.Response.Status, whiskErrorResponse.Response.Result)
 
-		errStr := getApplicationErrorMessage(whiskErrorResponse.Response.Result)
-		Debug(DbgInfo, "Application error received: %s\n", errStr)
+		errStr := c.applicationErrorMessage(whiskErrorResponse.Response.Result)
+		c.debug(DbgInfo, "Application error received: %s\n", errStr)
 
 		errMsg := wski18n.T("The following application error was received: {{.err}}",
 			map[string]interface{}{"err": errStr})
-		whiskErr := MakeWskError(errors.New(errMsg), resp.StatusCode-256, NO_DISPLAY_MSG, NO_DISPLAY_USAGE,
+		whiskErr := makeHttpWskError(resp, errors.New(errMsg), NO_DISPLAY_MSG, NO_DISPLAY_USAGE,
 			NO_MSG_DISPLAYED, DISPLAY_PREFIX, APPLICATION_ERR)
-		return parseSuccessResponse(resp, data, v), whiskErr
+		return c.parseSuccessResponse(resp, data, v), whiskErr
 	}
 
 	appErrResult := &AppErrorResult{}
@@ -603,24 +766,30 @@ func parseApplicationError(resp *http.Response, data []byte, v interface{}) (*ht
 
 	// Handle application errors that occur with blocking invocations when --result option is true (#5)
 	if err == nil && appErrResult.Error != nil {
-		Debug(DbgInfo, "Error code is null, blocking with result invocation error has occurred\n")
-		errStr := getApplicationErrorMessage(*appErrResult.Error)
-		Debug(DbgInfo, "Application error received: %s\n", errStr)
+		c.debug(DbgInfo, "Error code is null, blocking with result invocation error has occurred\n")
+		errStr := c.applicationErrorMessage(*appErrResult.Error)
+		c.debug(DbgInfo, "Application error received: %s\n", errStr)
 
-		whiskErr := MakeWskError(errors.New(errStr), resp.StatusCode-256, NO_DISPLAY_MSG, NO_DISPLAY_USAGE,
+		whiskErr := makeHttpWskError(resp, errors.New(errStr), NO_DISPLAY_MSG, NO_DISPLAY_USAGE,
 			NO_MSG_DISPLAYED, DISPLAY_PREFIX, APPLICATION_ERR)
-		return parseSuccessResponse(resp, data, v), whiskErr
+		return c.parseSuccessResponse(resp, data, v), whiskErr
 	}
 
 	// Body contents are unknown (#6)
-	Debug(DbgError, "HTTP response with unexpected body failed due to contents parsing error: '%v'\n", err)
+	c.debug(DbgError, "HTTP response with unexpected body failed due to contents parsing error: '%v'\n", err)
 	errMsg := wski18n.T("The connection failed, or timed out. (HTTP status code {{.code}})",
 		map[string]interface{}{"code": resp.StatusCode})
-	whiskErr := MakeWskError(errors.New(errMsg), resp.StatusCode-256, DISPLAY_MSG, NO_DISPLAY_USAGE)
+	whiskErr := makeHttpWskError(resp, errors.New(errMsg), DISPLAY_MSG, NO_DISPLAY_USAGE)
 	return resp, whiskErr
 }
 
 func getApplicationErrorMessage(errResp interface{}) string {
+	var c *Client
+	return c.applicationErrorMessage(errResp)
+}
+
+// applicationErrorMessage is getApplicationErrorMessage, tracing to the client's Logger.
+func (c *Client) applicationErrorMessage(errResp interface{}) string {
 	var errStr string
 
 	// Handle error results that looks like:
@@ -671,7 +840,7 @@ func getApplicationErrorMessage(errResp interface{}) string {
 					errStr = errorStr
 				}
 			} else {
-				Debug(DbgInfo, "Application failure error json: %+v\n", errObj)
+				c.debug(DbgInfo, "Application failure error json: %+v\n", errObj)
 
 				// Concatenate all string field values into a single error string
 				// Note: entry order of strings from map are not preserved.
@@ -700,8 +869,8 @@ func getApplicationErrorMessage(errResp interface{}) string {
 
 	return errStr
 }
-func parseSuccessResponse(resp *http.Response, data []byte, v interface{}) *http.Response {
-	Debug(DbgInfo, "Parsing HTTP response into struct type: %s\n", reflect.TypeOf(v))
+func (c *Client) parseSuccessResponse(resp *http.Response, data []byte, v interface{}) *http.Response {
+	c.debug(DbgInfo, "Parsing HTTP response into struct type: %s\n", reflect.TypeOf(v))
 
 	dc := json.NewDecoder(strings.NewReader(string(data)))
 	dc.UseNumber()
@@ -710,11 +879,11 @@ func parseSuccessResponse(resp *http.Response, data []byte, v interface{}) *http
 	// If the decode was successful, return the response without error (#1). Otherwise, the decode did not work, so the
 	// server response was unexpected (#3)
 	if err == nil {
-		Debug(DbgInfo, "Successful parse of HTTP response into struct type: %s\n", reflect.TypeOf(v))
+		c.debug(DbgInfo, "Successful parse of HTTP response into struct type: %s\n", reflect.TypeOf(v))
 		return resp
 	} else {
-		Debug(DbgWarn, "Unsuccessful parse of HTTP response into struct type: %s; parse error '%v'\n", reflect.TypeOf(v), err)
-		Debug(DbgWarn, "Request was successful, so ignoring the following unexpected response body that could not be parsed: %s\n", data)
+		c.debug(DbgWarn, "Unsuccessful parse of HTTP response into struct type: %s; parse error '%v'\n", reflect.TypeOf(v), err)
+		c.debug(DbgWarn, "Request was successful, so ignoring the following unexpected response body that could not be parsed: %s\n", data)
 		return resp
 	}
 }
@@ -762,6 +931,21 @@ func (r ErrorResponse) Error() string {
 		map[string]interface{}{"msg": fmt.Sprintf("%v", *r.ErrMsg), "code": r.Code})
 }
 
+// TransactionID returns the error code sent by the server, which is the transaction id of the failed request
+func (r ErrorResponse) TransactionID() string {
+	if r.Code == nil {
+		return ""
+	}
+	return fmt.Sprintf("%v", *r.Code)
+}
+
+// makeHttpWskError creates the WskError for a failed HTTP response, keeping the response's status code
+func makeHttpWskError(resp *http.Response, err error, flags ...bool) *WskError {
+	werr := MakeWskError(err, resp.StatusCode-256, flags...)
+	werr.StatusCode = resp.StatusCode
+	return werr
+}
+
 ////////////////////////////
 // Basic Client Functions //
 ////////////////////////////
@@ -771,13 +955,19 @@ func IsHttpRespSuccess(r *http.Response) bool {
 }
 
 func IsResponseResultSuccess(data []byte) bool {
+	var c *Client
+	return c.isResponseResultSuccess(data)
+}
+
+// isResponseResultSuccess is IsResponseResultSuccess, tracing the errors to the client's Logger.
+func (c *Client) isResponseResultSuccess(data []byte) bool {
 	errResp := new(WhiskErrorResponse)
 	err := json.Unmarshal(data, &errResp)
 
 	if errResp.Response != nil {
 		return errResp.Response.Success
 	} else if err != nil { //failed to parse WhiskErrorResponse
-		Debug(DbgWarn, "IsResponseResultSuccess: failed to parse response result: %v\n", err)
+		c.debug(DbgWarn, "IsResponseResultSuccess: failed to parse response result: %v\n", err)
 	}
 
 	return true
@@ -822,22 +1012,22 @@ func (c *Client) NewRequestUrl(
 		}
 
 		// Assemble the complete URL: base + version + [namespace] + resource_relative_path
-		Debug(DbgInfo, "basepath: %s, version/namespace path: %s, resource path: %s\n", c.BaseURL.String(), urlVerNamespaceStr, urlRelResource.String())
+		c.debug(DbgInfo, "basepath: %s, version/namespace path: %s, resource path: %s\n", c.BaseURL.String(), urlVerNamespaceStr, c.redactor().url(urlRelResource))
 		urlStr := fmt.Sprintf("%s/%s/%s", c.BaseURL.String(), urlVerNamespaceStr, urlRelResource.String())
 		requestUrl, err = url.Parse(urlStr)
 		if err != nil {
-			Debug(DbgError, "url.Parse(%s) error: %s\n", urlStr, err)
+			c.debug(DbgError, "url.Parse(%s) error: %s\n", urlStr, err)
 			errStr := wski18n.T("Invalid request URL '{{.url}}': {{.err}}",
 				map[string]interface{}{"url": urlStr, "err": err})
 			werr := MakeWskError(errors.New(errStr), EXIT_CODE_ERR_GENERAL, DISPLAY_MSG, NO_DISPLAY_USAGE)
 			return nil, werr
 		}
 	} else {
-		Debug(DbgInfo, "basepath: %s, resource path: %s\n", c.BaseURL.String(), urlRelResource.String())
+		c.debug(DbgInfo, "basepath: %s, resource path: %s\n", c.BaseURL.String(), c.redactor().url(urlRelResource))
 		urlStr := fmt.Sprintf("%s/%s", c.BaseURL.String(), urlRelResource.String())
 		requestUrl, err = url.Parse(urlStr)
 		if err != nil {
-			Debug(DbgError, "url.Parse(%s) error: %s\n", urlStr, err)
+			c.debug(DbgError, "url.Parse(%s) error: %s\n", urlStr, err)
 			errStr := wski18n.T("Invalid request URL '{{.url}}': {{.err}}",
 				map[string]interface{}{"url": urlStr, "err": err})
 			werr := MakeWskError(errors.New(errStr), EXIT_CODE_ERR_GENERAL, DISPLAY_MSG, NO_DISPLAY_USAGE)
@@ -854,7 +1044,7 @@ func (c *Client) NewRequestUrl(
 			err := encoder.Encode(body)
 
 			if err != nil {
-				Debug(DbgError, "json.Encode(%#v) error: %s\n", body, err)
+				c.debug(DbgError, "json.Encode(%#v) error: %s\n", body, err)
 				errStr := wski18n.T("Error encoding request body: {{.err}}",
 					map[string]interface{}{"err": err})
 				werr := MakeWskError(errors.New(errStr), EXIT_CODE_ERR_GENERAL, DISPLAY_MSG, NO_DISPLAY_USAGE)
@@ -864,13 +1054,13 @@ func (c *Client) NewRequestUrl(
 			if values, ok := body.(url.Values); ok {
 				buf = bytes.NewBufferString(values.Encode())
 			} else {
-				Debug(DbgError, "Invalid form data body: %v\n", body)
+				c.debug(DbgError, "Invalid form data body: %v\n", body)
 				errStr := wski18n.T("Internal error.  Form data encoding failure")
 				werr := MakeWskError(errors.New(errStr), EXIT_CODE_ERR_GENERAL, DISPLAY_MSG, NO_DISPLAY_USAGE)
 				return nil, werr
 			}
 		} else {
-			Debug(DbgError, "Invalid body encode type: %s\n", encodeBodyAs)
+			c.debug(DbgError, "Invalid body encode type: %s\n", encodeBodyAs)
 			errStr := wski18n.T("Internal error.  Invalid encoding type '{{.encodetype}}'",
 				map[string]interface{}{"encodetype": encodeBodyAs})
 			werr := MakeWskError(errors.New(errStr), EXIT_CODE_ERR_GENERAL, DISPLAY_MSG, NO_DISPLAY_USAGE)
@@ -880,7 +1070,7 @@ func (c *Client) NewRequestUrl(
 
 	req, err := http.NewRequest(method, requestUrl.String(), buf)
 	if err != nil {
-		Debug(DbgError, "http.NewRequest(%v, %s, buf) error: %s\n", method, requestUrl.String(), err)
+		c.debug(DbgError, "http.NewRequest(%v, %s, buf) error: %s\n", method, requestUrl.String(), err)
 		errStr := wski18n.T("Error initializing request: {{.err}}", map[string]interface{}{"err": err})
 		werr := MakeWskError(errors.New(errStr), EXIT_CODE_ERR_GENERAL, DISPLAY_MSG, NO_DISPLAY_USAGE)
 		return nil, werr
@@ -895,14 +1085,14 @@ func (c *Client) NewRequestUrl(
 	if useAuthentication {
 		err = c.addAuthHeader(req, AuthRequired)
 		if err != nil {
-			Debug(DbgError, "addAuthHeader() error: %s\n", err)
+			c.debug(DbgError, "addAuthHeader() error: %s\n", err)
 			errStr := wski18n.T("Unable to add the HTTP authentication header: {{.err}}",
 				map[string]interface{}{"err": err})
 			werr := MakeWskErrorFromWskError(errors.New(errStr), err, EXIT_CODE_ERR_GENERAL, DISPLAY_MSG, NO_DISPLAY_USAGE)
 			return nil, werr
 		}
 	} else {
-		Debug(DbgInfo, "No auth header required\n")
+		c.debug(DbgInfo, "No auth header required\n")
 	}
 
 	req.Header.Add("User-Agent", c.Config.UserAgent)
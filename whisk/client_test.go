@@ -20,12 +20,19 @@
 package whisk
 
 import (
+	"context"
 	"fmt"
+	"github.com/apache/openwhisk-client-go/wski18n"
 	"github.com/stretchr/testify/assert"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 const (
@@ -71,6 +78,18 @@ func GetValidConfigDiffApiHostAndBaseURLTest() *Config {
 	return &config
 }
 
+// newLocalTestClient returns a client sending its requests to the given test server.  Its transport does
+// not use the proxy environment, which http.ProxyFromEnvironment caches on first use (see TestProxyHost).
+func newLocalTestClient(t *testing.T, server *httptest.Server, config *Config) *Client {
+	if config == nil {
+		config = GetValidConfigTest()
+	}
+	config.Host = server.URL
+	client, err := NewClient(&http.Client{Transport: &http.Transport{}}, config)
+	assert.Nil(t, err)
+	return client
+}
+
 func TestNewClient(t *testing.T) {
 	// Test the use case to pass a valid config.
 	config := GetValidConfigTest()
@@ -107,6 +126,31 @@ func TestNewClient(t *testing.T) {
 	assert.Equal(t, FakeAuthKey, client.Config.AuthToken)
 }
 
+func TestNewClientKeepsConfig(t *testing.T) {
+	// The defaults are filled in the caller's configuration, as they always were, and in the client's copy of it
+	config := GetValidConfigTest()
+	config.Namespace = ""
+	client, err := NewClient(http.DefaultClient, config)
+	assert.Nil(t, err)
+	assert.Equal(t, FakeBaseURL, config.BaseURL.String())
+	assert.Equal(t, "_", config.Namespace)
+	assert.Equal(t, "v1", config.Version)
+	assert.NotEqual(t, "", config.UserAgent)
+	assert.True(t, client.Config != config)
+	assert.True(t, client.Config.BaseURL != config.BaseURL)
+	assert.Equal(t, FakeBaseURL, client.Config.BaseURL.String())
+	assert.Equal(t, "_", client.Config.Namespace)
+	assert.Equal(t, "v1", client.Config.Version)
+	assert.NotEqual(t, "", client.Config.UserAgent)
+
+	// The caller's base URL is copied
+	config = GetValidConfigDiffApiHostAndBaseURLTest()
+	client, err = NewClient(http.DefaultClient, config)
+	assert.Nil(t, err)
+	config.BaseURL.Path = "/changed"
+	assert.Equal(t, FakeBaseURLDiff, client.Config.BaseURL.String())
+}
+
 func TestProxyHost(t *testing.T) {
 	var proxyhost = "one.bad.url.going.nowhere.org"
 	var proxyurl = "https://" + proxyhost
@@ -190,3 +234,147 @@ func TestParseApplicationError(t *testing.T) {
 	errStr = getApplicationErrorMessage(appErr2)
 	assert.Equal(t, "Another error string", errStr)
 }
+
+func TestDoContextCanceled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer server.Close()
+
+	client := newLocalTestClient(t, server, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, _, err := client.Actions.InvokeContext(ctx, "test", nil, true, false)
+	assert.NotNil(t, err)
+	assert.True(t, time.Since(start) < 5*time.Second, "request was not canceled by the context deadline")
+}
+
+func TestWithNamespace(t *testing.T) {
+	var lock sync.Mutex
+	paths := map[string]bool{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		paths[r.URL.Path] = true
+		lock.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte("[]"))
+	}))
+	defer server.Close()
+
+	client := newLocalTestClient(t, server, nil)
+	other := client.WithNamespace("other")
+	assert.Equal(t, "other", other.Config.Namespace)
+	assert.Equal(t, "_", client.WithNamespace("").Config.Namespace)
+
+	// The services do not change the namespace of the shared configuration
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(4)
+		go func() {
+			defer wg.Done()
+			client.Activations.List(nil)
+		}()
+		go func() {
+			defer wg.Done()
+			client.Namespaces.List()
+		}()
+		go func() {
+			defer wg.Done()
+			client.Actions.List("", nil)
+		}()
+		go func() {
+			defer wg.Done()
+			other.Actions.List("", nil)
+		}()
+	}
+	wg.Wait()
+
+	// The activations are in "_", unless the namespace is chosen with WithNamespace
+	_, _, err := other.Activations.List(nil)
+	assert.Nil(t, err)
+
+	assert.Equal(t, FakeNamespace, client.Config.Namespace)
+	assert.Equal(t, "other", other.Config.Namespace)
+	assert.Equal(t, map[string]bool{
+		"/api/v1/namespaces/_/activations":                 true,
+		"/api/v1/namespaces":                               true,
+		"/api/v1/namespaces/" + FakeNamespace + "/actions": true,
+		"/api/v1/namespaces/other/actions":                 true,
+		"/api/v1/namespaces/other/activations":             true,
+	}, paths)
+}
+
+// TestClientConcurrentUse shares a client between goroutines; run it with -race.
+func TestClientConcurrentUse(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/actions/hello"):
+			w.Write([]byte(`{"namespace":"` + FakeNamespace + `","name":"hello"}`))
+		case strings.HasSuffix(r.URL.Path, "/actions"), strings.HasSuffix(r.URL.Path, "/activations"),
+			strings.HasSuffix(r.URL.Path, "/namespaces"):
+			w.Write([]byte(`[]`))
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+
+	config := GetValidConfigTest()
+	config.AdditionalHeaders = http.Header{"X-Test": []string{"shared"}}
+	client := newLocalTestClient(t, server, config)
+
+	// The client does not share the caller's configuration
+	config.Namespace = "changed"
+	config.BaseURL.Path = "/changed"
+	config.AdditionalHeaders.Set("X-Test", "changed")
+	assert.Equal(t, FakeNamespace, client.Config.Namespace)
+	assert.Equal(t, "/api", client.Config.BaseURL.Path)
+	assert.Equal(t, "shared", client.Config.AdditionalHeaders.Get("X-Test"))
+
+	const goroutines = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, goroutines*6)
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			SetVerbose(false)
+			SetDebug(IsDebug())
+			if i%5 == 0 {
+				wski18n.InitWithLocale(wski18n.DEFAULT_LOCALE)
+				client.Use(func(next Handler) Handler { return next })
+			}
+
+			_, _, err := client.Actions.Get("hello", false)
+			errs <- err
+			_, _, err = client.Actions.List("", nil)
+			errs <- err
+			_, _, err = client.WithNamespace("other").Actions.List("", nil)
+			errs <- err
+			_, _, err = client.Activations.List(nil)
+			errs <- err
+			_, _, err = client.Namespaces.List()
+			errs <- err
+			_, err = client.Sdks.Install("blackbox.tar.gz")
+			errs <- err
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		assert.Nil(t, err)
+	}
+	assert.Equal(t, int32(goroutines*6), atomic.LoadInt32(&requests))
+	assert.Equal(t, FakeNamespace, client.Config.Namespace)
+	assert.Equal(t, "/api", client.Config.BaseURL.Path)
+}
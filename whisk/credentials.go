@@ -0,0 +1,191 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package whisk
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/apache/openwhisk-client-go/wski18n"
+)
+
+// Refreshed tokens are renewed this long before they expire, so that they do not expire in flight
+const DEFAULT_TOKEN_EXPIRY_MARGIN = 30 * time.Second
+
+// CredentialProvider supplies the Authorization header of the client's requests, configured with
+// Config.Credentials.  It is consulted for every request, and takes precedence over Config.AuthToken;
+// an Authorization header set in Config.AdditionalHeaders still overrides both.
+type CredentialProvider interface {
+	// Authorization returns the value of the Authorization header, e.g. "Bearer <token>"
+	Authorization(ctx context.Context) (string, error)
+}
+
+// CredentialInvalidator is implemented by the providers whose credentials can be renewed.  When a request is
+// rejected with an HTTP 401, the client invalidates the rejected value and retries the request once with the
+// value the provider returns next.
+type CredentialInvalidator interface {
+	Invalidate(authorization string)
+}
+
+// TokenSource fetches a new bearer token, along with its expiry time.  A zero expiry means the token does
+// not expire.
+type TokenSource func(ctx context.Context) (token string, expiry time.Time, err error)
+
+type staticCredentials string
+
+func (credentials staticCredentials) Authorization(ctx context.Context) (string, error) {
+	return string(credentials), nil
+}
+
+// NewBasicAuthCredentials returns a provider of basic authentication with an OpenWhisk auth key
+// ("<uuid>:<key>"), as done for Config.AuthToken.
+func NewBasicAuthCredentials(authKey string) CredentialProvider {
+	return staticCredentials("Basic " + base64.StdEncoding.EncodeToString([]byte(authKey)))
+}
+
+// NewBearerCredentials returns a provider of a static bearer token.
+func NewBearerCredentials(token string) CredentialProvider {
+	return staticCredentials("Bearer " + token)
+}
+
+// NewRefreshingCredentials returns a provider of bearer tokens fetched from source.  A token is reused until
+// it is about to expire or it is rejected by the server; only one refresh is in flight at a time, the other
+// requests wait for it.
+func NewRefreshingCredentials(source TokenSource) CredentialProvider {
+	return &refreshingCredentials{source: source, margin: DEFAULT_TOKEN_EXPIRY_MARGIN}
+}
+
+type refreshingCredentials struct {
+	source TokenSource
+	margin time.Duration
+
+	mu            sync.Mutex // Held during a refresh
+	authorization string
+	expiry        time.Time
+}
+
+func (r *refreshingCredentials) Authorization(ctx context.Context) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.authorization) > 0 && (r.expiry.IsZero() || time.Now().Add(r.margin).Before(r.expiry)) {
+		return r.authorization, nil
+	}
+
+	c := contextClient(ctx)
+	c.debug(DbgInfo, "Refreshing the bearer token\n")
+	token, expiry, err := r.source(ctx)
+	if err != nil {
+		c.debug(DbgError, "Bearer token refresh error: %s\n", err)
+		return "", err
+	}
+	r.authorization, r.expiry = "Bearer "+token, expiry
+	return r.authorization, nil
+}
+
+func (r *refreshingCredentials) Invalidate(authorization string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Another request may have refreshed the token already
+	if r.authorization == authorization {
+		r.authorization = ""
+	}
+}
+
+type clientContextKey struct{}
+
+// credentialsContext returns the context of a call, with the client, so that the providers trace to its Logger.
+func (c *Client) credentialsContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, clientContextKey{}, c)
+}
+
+// contextClient returns the client of a context returned by credentialsContext, or nil.
+func contextClient(ctx context.Context) *Client {
+	c, _ := ctx.Value(clientContextKey{}).(*Client)
+	return c
+}
+
+// authorize returns the request created by NewRequest with its Authorization header set from the credentials, with
+// the context of the call, before the request goes through the middleware: the request is created before the call
+// gets its context, and the credentials may have been refreshed since.  The requests created without authentication
+// have no Authorization header.  A provider failure is a WskError matching ErrCredentials, which is not retried.
+func (c *Client) authorize(ctx context.Context, req *http.Request) (*http.Request, error) {
+	_, authenticated := req.Header["Authorization"]
+	if c.Config.Credentials == nil || !authenticated || len(c.Config.AdditionalHeaders.Get("Authorization")) > 0 {
+		return req, nil
+	}
+
+	current, err := c.Config.Credentials.Authorization(c.credentialsContext(ctx))
+	if err != nil {
+		c.debug(DbgError, "Credentials.Authorization() error: %s\n", err)
+		c.log(LogError, "HTTP request credentials unavailable", LogFields{"method": req.Method, "url": c.redactedURL(req),
+			"error": err})
+		errStr := wski18n.T("Unable to get the credentials of the request")
+		return nil, MakeWskError(fmt.Errorf("%s: %w", errStr, err), EXIT_CODE_ERR_CREDENTIALS, DISPLAY_MSG, NO_DISPLAY_USAGE)
+	}
+	if current != req.Header.Get("Authorization") {
+		// Clone the header, which is shared with the caller's request
+		req = req.Clone(req.Context())
+		req.Header.Set("Authorization", current)
+	}
+	return req, nil
+}
+
+// sendAuthenticated sends the request, renewing the credentials and sending it again once if the server
+// rejects them.
+func (c *Client) sendAuthenticated(req *http.Request) (*http.Response, error) {
+	resp, err := c.roundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	invalidator, ok := c.Config.Credentials.(CredentialInvalidator)
+	authorization := req.Header.Get("Authorization")
+	if !ok || len(authorization) == 0 || len(c.Config.AdditionalHeaders.Get("Authorization")) > 0 {
+		return resp, err
+	}
+
+	invalidator.Invalidate(authorization)
+	renewed, credErr := c.Config.Credentials.Authorization(c.credentialsContext(req.Context()))
+	if credErr != nil || renewed == authorization {
+		return resp, err
+	}
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return resp, err
+	}
+	retry, rewindErr := c.rewindRequest(req)
+	if rewindErr != nil {
+		return resp, err
+	}
+
+	c.debug(DbgInfo, "HTTP %s %s was not authorized; retrying with renewed credentials\n", req.Method, c.redactedURL(req))
+	c.log(LogInfo, "Retrying HTTP request with renewed credentials", LogFields{"method": req.Method, "url": c.redactedURL(req),
+		"status": resp.StatusCode})
+	io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+
+	retry.Header.Set("Authorization", renewed)
+	return c.roundTrip(retry)
+}
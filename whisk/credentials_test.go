@@ -0,0 +1,235 @@
+// +build unit
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package whisk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// newAuthServer accepts the requests authorized with `valid`, and records the authorization and body of every request.
+func newAuthServer(valid *string, authorizations *[]string, bodies *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		*authorizations = append(*authorizations, r.Header.Get("Authorization"))
+		*bodies = append(*bodies, string(body))
+		w.Header().Set("Content-Type", "application/json")
+		if r.Header.Get("Authorization") != *valid {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":"The supplied authentication is invalid","code":"d4e5f6"}`))
+			return
+		}
+		w.Write([]byte(`{"name":"test","namespace":"my_namespace"}`))
+	}))
+}
+
+func TestStaticCredentials(t *testing.T) {
+	authorization, _ := NewBasicAuthCredentials("user:pass").Authorization(context.Background())
+	assert.Equal(t, "Basic dXNlcjpwYXNz", authorization)
+	authorization, _ = NewBearerCredentials("token").Authorization(context.Background())
+	assert.Equal(t, "Bearer token", authorization)
+}
+
+func TestCredentialProviderOverridesAuthToken(t *testing.T) {
+	valid := "Bearer static"
+	var authorizations, bodies []string
+	server := newAuthServer(&valid, &authorizations, &bodies)
+	defer server.Close()
+
+	config := GetValidConfigTest()
+	config.Credentials = NewBearerCredentials("static")
+	client := newLocalTestClient(t, server, config)
+
+	_, _, err := client.Triggers.Get("test")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Bearer static"}, authorizations)
+}
+
+func TestRefreshingCredentials(t *testing.T) {
+	refreshes := 0
+	expiry := time.Now().Add(time.Hour)
+	credentials := NewRefreshingCredentials(func(ctx context.Context) (string, time.Time, error) {
+		refreshes++
+		return fmt.Sprintf("token%d", refreshes), expiry, nil
+	})
+
+	authorization, _ := credentials.Authorization(context.Background())
+	assert.Equal(t, "Bearer token1", authorization)
+	authorization, _ = credentials.Authorization(context.Background())
+	assert.Equal(t, "Bearer token1", authorization)
+
+	// Invalidating a stale value keeps the current token
+	credentials.(CredentialInvalidator).Invalidate("Bearer token0")
+	authorization, _ = credentials.Authorization(context.Background())
+	assert.Equal(t, "Bearer token1", authorization)
+
+	credentials.(CredentialInvalidator).Invalidate("Bearer token1")
+	authorization, _ = credentials.Authorization(context.Background())
+	assert.Equal(t, "Bearer token2", authorization)
+
+	// A token about to expire is refreshed
+	expiry = time.Now().Add(time.Second)
+	credentials.(CredentialInvalidator).Invalidate("Bearer token2")
+	credentials.Authorization(context.Background())
+	authorization, _ = credentials.Authorization(context.Background())
+	assert.Equal(t, "Bearer token4", authorization)
+}
+
+func TestRetryOnUnauthorized(t *testing.T) {
+	valid := "Bearer token2"
+	var authorizations, bodies []string
+	server := newAuthServer(&valid, &authorizations, &bodies)
+	defer server.Close()
+
+	refreshes := 0
+	config := GetValidConfigTest()
+	config.Credentials = NewRefreshingCredentials(func(ctx context.Context) (string, time.Time, error) {
+		refreshes++
+		return fmt.Sprintf("token%d", refreshes), time.Time{}, nil
+	})
+	client := newLocalTestClient(t, server, config)
+
+	_, _, err := client.Triggers.Insert(&Trigger{Name: "test"}, true)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Bearer token1", "Bearer token2"}, authorizations)
+	assert.Equal(t, bodies[0], bodies[1])
+
+	// The request is only retried once
+	valid = "Bearer none"
+	authorizations = nil
+	_, resp, err := client.Triggers.Get("test")
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.Equal(t, []string{"Bearer token2", "Bearer token3"}, authorizations)
+}
+
+func TestCredentialsUseCallContext(t *testing.T) {
+	valid := "Bearer token1"
+	var authorizations, bodies []string
+	server := newAuthServer(&valid, &authorizations, &bodies)
+	defer server.Close()
+
+	var ids []string
+	config := GetValidConfigTest()
+	config.Credentials = NewRefreshingCredentials(func(ctx context.Context) (string, time.Time, error) {
+		ids = append(ids, correlationId(ctx))
+		return fmt.Sprintf("token%d", len(ids)), time.Now(), nil
+	})
+	client := newLocalTestClient(t, server, config)
+
+	// The token is only fetched when the request is sent, with the context of the call
+	ctx := WithCorrelationId(context.Background(), "my-id")
+	_, _, err := client.Triggers.(TriggerServiceContextInterface).GetContext(ctx, "test")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"my-id"}, ids)
+	assert.Equal(t, []string{"Bearer token1"}, authorizations)
+
+	// The token expires at once, so it is fetched again for the next call, with its context
+	ctx = WithCorrelationId(context.Background(), "other-id")
+	valid = "Bearer token2"
+	authorizations = nil
+	_, _, err = client.Triggers.(TriggerServiceContextInterface).GetContext(ctx, "test")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"my-id", "other-id"}, ids)
+	assert.Equal(t, []string{"Bearer token2"}, authorizations)
+
+	// The requests created without authentication are sent without credentials
+	req, err := client.NewRequestUrl("GET", client.Config.BaseURL, nil, false, false, EncodeBodyAsJson, NoAuth)
+	assert.Nil(t, err)
+	_, hasAuthorization := req.Header["Authorization"]
+	assert.False(t, hasAuthorization)
+	req, err = client.NewRequest("GET", "triggers/test", nil, true)
+	assert.Nil(t, err)
+	assert.Equal(t, "", req.Header.Get("Authorization"))
+	assert.Equal(t, []string{"my-id", "other-id"}, ids)
+
+	// A canceled call does not refresh the token
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	config.Credentials = NewRefreshingCredentials(func(ctx context.Context) (string, time.Time, error) {
+		if ctx.Err() != nil {
+			return "", time.Time{}, ctx.Err()
+		}
+		return "token1", time.Now(), nil
+	})
+	client = newLocalTestClient(t, server, config)
+	authorizations = nil
+	_, _, err = client.Triggers.(TriggerServiceContextInterface).GetContext(canceled, "test")
+	assert.NotNil(t, err)
+	assert.Nil(t, authorizations)
+}
+
+func TestCredentialsBeforeMiddleware(t *testing.T) {
+	valid := "Bearer token1"
+	var authorizations, bodies []string
+	server := newAuthServer(&valid, &authorizations, &bodies)
+	defer server.Close()
+
+	config := GetValidConfigTest()
+	config.Credentials = NewRefreshingCredentials(func(ctx context.Context) (string, time.Time, error) {
+		return "token1", time.Time{}, nil
+	})
+	client := newLocalTestClient(t, server, config)
+
+	// The middleware see the request with its credentials
+	var seen []string
+	client.Use(func(next Handler) Handler {
+		return func(ctx context.Context, req *http.Request, v interface{}) (*http.Response, error) {
+			seen = append(seen, req.Header.Get("Authorization"))
+			return next(ctx, req, v)
+		}
+	})
+	_, _, err := client.Triggers.Get("test")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Bearer token1"}, seen)
+	assert.Equal(t, []string{"Bearer token1"}, authorizations)
+}
+
+func TestCredentialsProviderError(t *testing.T) {
+	valid := "Bearer token1"
+	var authorizations, bodies []string
+	server := newAuthServer(&valid, &authorizations, &bodies)
+	defer server.Close()
+
+	failure := errors.New("token endpoint unavailable")
+	refreshes := 0
+	config := GetValidConfigTest()
+	config.Retry = &RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}
+	config.Credentials = NewRefreshingCredentials(func(ctx context.Context) (string, time.Time, error) {
+		refreshes++
+		return "", time.Time{}, failure
+	})
+	client := newLocalTestClient(t, server, config)
+
+	// A provider failure is neither a network error nor retried, and no request is sent
+	_, _, err := client.Triggers.Get("test")
+	assert.True(t, errors.Is(err, ErrCredentials))
+	assert.True(t, errors.Is(err, failure))
+	assert.Equal(t, EXIT_CODE_ERR_CREDENTIALS, err.(*WskError).ExitCode)
+	assert.Equal(t, 1, refreshes)
+	assert.Nil(t, authorizations)
+}
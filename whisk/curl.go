@@ -0,0 +1,130 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package whisk
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// CurlCommand returns a curl command line sending the same request as req, e.g. one created by NewRequest, with
+// its method, URL, headers and body.  When redact is true, the secrets of DefaultRedactionPolicy, such as the
+// Authorization header, are replaced with "******".  The body of req is left unread.
+//
+// The requests of a client with Config.Credentials only get their Authorization header when they are sent, and
+// their command has none: use Client.CurlCommand, which gets it from the credentials.
+func CurlCommand(req *http.Request, redact bool) (string, error) {
+	var r *redactor
+	if redact {
+		r = newRedactor(nil)
+	}
+	var c *Client
+	return c.curlCommand(req, r, false)
+}
+
+// CurlCommand is like the CurlCommand function, but the secrets of the client's RedactionPolicy are redacted too,
+// and the command skips the verification of the server certificate when the client does.  With Config.Credentials,
+// the Authorization header is the one of the credentials, got with the context of req; a provider failure is a
+// WskError matching ErrCredentials.
+func (c *Client) CurlCommand(req *http.Request, redact bool) (string, error) {
+	req, err := c.authorize(req.Context(), req)
+	if err != nil {
+		return "", err
+	}
+	var r *redactor
+	if redact {
+		r = c.redactor()
+	}
+	return c.curlCommand(req, r, c.Config.Insecure)
+}
+
+// curlCommand returns the curl command of the request, redacted by r unless it is nil.  The errors are traced by
+// the client c, or to stdout if it is nil.
+func (c *Client) curlCommand(req *http.Request, r *redactor, insecure bool) (string, error) {
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		if req.GetBody != nil {
+			reader, getErr := req.GetBody()
+			if getErr != nil {
+				return "", getErr
+			}
+			body, err = ioutil.ReadAll(reader)
+			reader.Close()
+		} else {
+			body, err = ioutil.ReadAll(req.Body)
+			req.Body.Close()
+			req.Body = ioutil.NopCloser(bytes.NewReader(body))
+		}
+		if err != nil {
+			c.debug(DbgError, "ioutil.ReadAll(req.Body) error: %s\n", err)
+			return "", MakeWskError(err, EXIT_CODE_ERR_GENERAL, DISPLAY_MSG, NO_DISPLAY_USAGE)
+		}
+	}
+
+	header, url, text := req.Header, req.URL.String(), string(body)
+	if r != nil {
+		header, url = r.header(req.Header), r.url(req.URL)
+		if len(body) > 0 {
+			text = r.body(body, nil)
+		}
+	}
+
+	args := []string{"curl"}
+	if insecure {
+		args = append(args, "--insecure")
+	}
+	args = append(args, "-X", shellQuote(req.Method), shellQuote(url))
+	if len(req.Host) > 0 && req.Host != req.URL.Host {
+		args = append(args, "-H", shellQuote("Host: "+req.Host))
+	}
+
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, value := range header[name] {
+			args = append(args, "-H", shellQuote(name+": "+value))
+		}
+	}
+
+	if len(body) > 0 {
+		args = append(args, "--data-binary", shellQuote(text))
+	}
+	return strings.Join(args, " "), nil
+}
+
+// shellQuote quotes a word for a POSIX shell, unless it only holds safe characters.
+func shellQuote(word string) string {
+	safe := len(word) > 0
+	for _, c := range word {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || strings.ContainsRune("-_./:=@,%+", c)) {
+			safe = false
+			break
+		}
+	}
+	if safe {
+		return word
+	}
+	return "'" + strings.Replace(word, "'", `'\''`, -1) + "'"
+}
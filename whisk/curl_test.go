@@ -0,0 +1,104 @@
+// +build unit
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package whisk
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCurlCommand(t *testing.T) {
+	config := GetValidConfigTest()
+	config.UserAgent = "test-agent"
+	config.Insecure = true
+	client, err := NewClient(http.DefaultClient, config)
+	assert.Nil(t, err)
+
+	req, err := client.NewRequest("PUT", "triggers/test?overwrite=true", map[string]string{"name": "it's"}, true)
+	assert.Nil(t, err)
+
+	auth := "Basic " + base64.StdEncoding.EncodeToString([]byte(FakeAuthKey))
+	expected := "curl --insecure -X PUT 'https://myUrl.com/api/v1/namespaces/my_namespace/triggers/test?overwrite=true'" +
+		" -H 'Authorization: %s' -H 'Content-Type: application/json' -H 'User-Agent: test-agent'" +
+		` --data-binary '{"name":"it'\''s"}` + "\n'"
+
+	curl, err := client.CurlCommand(req, true)
+	assert.Nil(t, err)
+	assert.Equal(t, strings.Replace(expected, "%s", REDACTED, 1), curl)
+
+	curl, err = client.CurlCommand(req, false)
+	assert.Nil(t, err)
+	assert.Equal(t, strings.Replace(expected, "%s", auth, 1), curl)
+
+	// The request can still be sent
+	body, err := ioutil.ReadAll(req.Body)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"name":"it's"}`+"\n", string(body))
+
+	curl, err = CurlCommand(req, true)
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(curl, "curl -X PUT 'https://"))
+}
+
+func TestCurlCommandCredentials(t *testing.T) {
+	config := GetValidConfigTest()
+	config.Credentials = NewBearerCredentials("t0k3n")
+	client, err := NewClient(http.DefaultClient, config)
+	assert.Nil(t, err)
+
+	req, err := client.NewRequest("GET", "actions/hello", nil, true)
+	assert.Nil(t, err)
+
+	curl, err := client.CurlCommand(req, false)
+	assert.Nil(t, err)
+	assert.Contains(t, curl, "-H 'Authorization: Bearer t0k3n'")
+	curl, err = client.CurlCommand(req, true)
+	assert.Nil(t, err)
+	assert.Contains(t, curl, "-H 'Authorization: "+REDACTED+"'")
+	assert.NotContains(t, curl, "t0k3n")
+
+	// The request itself is left unchanged
+	assert.Equal(t, "", req.Header.Get("Authorization"))
+
+	config.Credentials = NewRefreshingCredentials(func(ctx context.Context) (string, time.Time, error) {
+		return "", time.Time{}, errors.New("no token")
+	})
+	client, err = NewClient(http.DefaultClient, config)
+	assert.Nil(t, err)
+	req, err = client.NewRequest("GET", "actions/hello", nil, true)
+	assert.Nil(t, err)
+	_, err = client.CurlCommand(req, true)
+	assert.True(t, errors.Is(err, ErrCredentials))
+}
+
+func TestShellQuote(t *testing.T) {
+	assert.Equal(t, "GET", shellQuote("GET"))
+	assert.Equal(t, "https://example.com/api/v1", shellQuote("https://example.com/api/v1"))
+	assert.Equal(t, "''", shellQuote(""))
+	assert.Equal(t, "'a b'", shellQuote("a b"))
+	assert.Equal(t, `'$HOME'\''s'`, shellQuote("$HOME's"))
+}
@@ -0,0 +1,167 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package whisk
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+)
+
+const (
+	DEFAULT_FOLLOW_INTERVAL = 2 * time.Second
+	DEFAULT_FOLLOW_LOOKBACK = time.Minute
+)
+
+// FollowOptions controls how ActivationService.Follow polls for new activations
+type FollowOptions struct {
+	Name     string        // Only follow the activations of this entity; empty follows every activation in the namespace
+	Since    time.Time     // Only report activations started after this time.  Default is the time Follow is called
+	Interval time.Duration // Delay between two polls.  Default is 2s
+	Lookback time.Duration // Keep listing this far before the newest activation seen, to catch late records.  Default is 1m
+}
+
+// FollowHandler is called once for every new activation, oldest first, with the activation's logs filled in.
+// Returning an error stops Follow, which then returns that error.
+type FollowHandler func(activation *Activation) error
+
+// Follow polls the activation list like `wsk activation poll`, and calls the handler for every activation it
+// has not reported yet.  It keeps polling through transient failures (network errors, throttling, server errors)
+// until another failure, or an error from the handler, which it returns.  Use FollowContext to stop it.
+func (s *ActivationService) Follow(options *FollowOptions, handler FollowHandler) error {
+	return s.FollowContext(context.Background(), options, handler)
+}
+
+// FollowContext is like Follow, and returns nil once the context is canceled.
+func (s *ActivationService) FollowContext(ctx context.Context, options *FollowOptions, handler FollowHandler) error {
+	var opts FollowOptions
+	if options != nil {
+		opts = *options
+	}
+	if opts.Since.IsZero() {
+		opts.Since = time.Now()
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = DEFAULT_FOLLOW_INTERVAL
+	}
+	if opts.Lookback <= 0 {
+		opts.Lookback = DEFAULT_FOLLOW_LOOKBACK
+	}
+
+	since := toMillis(opts.Since)
+	newest := since
+	seen := make(map[string]int64)
+
+	for {
+		err := s.followOnce(ctx, opts.Name, since, seen, &newest, handler)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			if handlerErr, ok := err.(followHandlerError); ok {
+				return handlerErr.err
+			}
+			if !isTransientError(err) {
+				s.client.debug(DbgError, "Following activations failed: %s\n", err)
+				return err
+			}
+			s.client.debug(DbgWarn, "Following activations failed: %s; polling again in %v\n", err, opts.Interval)
+		}
+
+		// Keep listing a bit before the newest activation, and forget what can no longer be listed
+		if lookback := newest - int64(opts.Lookback/time.Millisecond); lookback > since {
+			since = lookback
+		}
+		for id, start := range seen {
+			if start < since {
+				delete(seen, id)
+			}
+		}
+
+		if sleepContext(ctx, opts.Interval) != nil {
+			return nil
+		}
+	}
+}
+
+// followHandlerError tells errors returned by the FollowHandler apart from the errors of the requests
+type followHandlerError struct {
+	err error
+}
+
+func (e followHandlerError) Error() string {
+	return e.err.Error()
+}
+
+func (s *ActivationService) followOnce(ctx context.Context, name string, since int64, seen map[string]int64, newest *int64, handler FollowHandler) error {
+	activations, err := s.ListAllContext(ctx, &ActivationListOptions{Name: name, Since: since}).All()
+	if err != nil {
+		return err
+	}
+
+	// Activations are listed newest first
+	for i := len(activations) - 1; i >= 0; i-- {
+		activation := activations[i]
+		if _, ok := seen[activation.ActivationID]; ok {
+			continue
+		}
+
+		logs, _, err := s.LogsContext(ctx, activation.ActivationID)
+		if err != nil {
+			if isTransientError(err) {
+				// Report this activation, and the ones after it, on the next poll
+				return err
+			}
+			s.client.debug(DbgWarn, "Unable to get the logs of activation '%s': %s\n", activation.ActivationID, err)
+		} else {
+			activation.Logs = logs.Logs
+		}
+
+		seen[activation.ActivationID] = activation.Start
+		if activation.Start > *newest {
+			*newest = activation.Start
+		}
+		if err = handler(&activation); err != nil {
+			return followHandlerError{err}
+		}
+	}
+
+	return nil
+}
+
+// isTransientError returns true for failures that may succeed when retried: network errors, and HTTP 5xx and
+// 429 (throttled) responses.
+func isTransientError(err error) bool {
+	var whiskErr *WskError
+	if errors.As(err, &whiskErr) {
+		if status := whiskErr.StatusCode; status != 0 {
+			return status >= 500 || status == http.StatusTooManyRequests
+		}
+		if whiskErr.ExitCode == EXIT_CODE_ERR_NETWORK {
+			return true
+		}
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+func toMillis(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
@@ -0,0 +1,127 @@
+// +build unit
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package whisk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newFollowServer lists one more activation on every poll, newest first, and fails the polls listed in
+// failures with the given status.
+func newFollowServer(start int64, failures map[int]int) *httptest.Server {
+	polls := 0
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/logs") {
+			parts := strings.Split(r.URL.Path, "/")
+			id := parts[len(parts)-2]
+			json.NewEncoder(w).Encode(map[string]interface{}{"logs": []string{"log of " + id}})
+			return
+		}
+
+		polls++
+		if status, ok := failures[polls]; ok {
+			w.WriteHeader(status)
+			w.Write([]byte(`{"error":"failed","code":"a1b2c3"}`))
+			return
+		}
+		activations := []map[string]interface{}{}
+		for i := polls; i > 0; i-- {
+			activations = append(activations, map[string]interface{}{
+				"name":         "test",
+				"activationId": fmt.Sprintf("id%d", i),
+				"start":        start + int64(i),
+			})
+		}
+		json.NewEncoder(w).Encode(activations)
+	}))
+}
+
+func TestActivationFollow(t *testing.T) {
+	server := newFollowServer(toMillis(time.Now()), map[int]int{2: http.StatusServiceUnavailable})
+	defer server.Close()
+	client := newLocalTestClient(t, server, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var ids, logs []string
+	err := client.Activations.FollowContext(ctx, &FollowOptions{Name: "test", Interval: time.Millisecond}, func(activation *Activation) error {
+		ids = append(ids, activation.ActivationID)
+		logs = append(logs, activation.Logs...)
+		if len(ids) == 4 {
+			cancel()
+		}
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"id1", "id2", "id3", "id4"}, ids)
+	assert.Equal(t, []string{"log of id1", "log of id2", "log of id3", "log of id4"}, logs)
+}
+
+func TestActivationFollowHandlerError(t *testing.T) {
+	server := newFollowServer(toMillis(time.Now()), nil)
+	defer server.Close()
+	client := newLocalTestClient(t, server, nil)
+
+	stop := errors.New("stop")
+	err := client.Activations.Follow(&FollowOptions{Interval: time.Millisecond}, func(activation *Activation) error {
+		return stop
+	})
+	assert.Equal(t, stop, err)
+}
+
+func TestActivationFollowPermanentError(t *testing.T) {
+	server := newFollowServer(toMillis(time.Now()), map[int]int{1: http.StatusUnauthorized})
+	defer server.Close()
+	client := newLocalTestClient(t, server, nil)
+
+	err := client.Activations.Follow(&FollowOptions{Interval: time.Millisecond}, func(activation *Activation) error {
+		return nil
+	})
+	assert.NotNil(t, err)
+}
+
+func TestIsTransientError(t *testing.T) {
+	httpError := func(status int) error {
+		werr := MakeWskError(errors.New("failed"), EXIT_CODE_ERR_GENERAL)
+		werr.StatusCode = status
+		return werr
+	}
+	assert.True(t, isTransientError(httpError(http.StatusServiceUnavailable)))
+	assert.True(t, isTransientError(httpError(http.StatusTooManyRequests)))
+	assert.False(t, isTransientError(httpError(http.StatusNotFound)))
+	assert.False(t, isTransientError(MakeWskError(errors.New("invalid"), EXIT_CODE_ERR_GENERAL)))
+	assert.False(t, isTransientError(errors.New("invalid")))
+
+	server := newFollowServer(toMillis(time.Now()), nil)
+	client := newLocalTestClient(t, server, nil)
+	server.Close()
+	_, _, err := client.Activations.List(nil)
+	assert.True(t, isTransientError(err), "network errors are transient")
+}
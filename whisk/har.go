@@ -0,0 +1,429 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package whisk
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptrace"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/apache/openwhisk-client-go/wski18n"
+)
+
+// Permissions of a saved HAR file, which holds the traffic of the client
+const HAR_FILE_MODE os.FileMode = 0600
+
+// Number of entries kept by the recorders of NewHARRecorder
+const DEFAULT_HAR_MAX_ENTRIES = 1000
+
+/*
+HARRecorder records the HTTP traffic of the clients configured with it, see Config.HAR, as a HAR 1.2 log: every
+request sent by Client.Do and SdkService.Install, with its response and timings.  The bodies are recorded in full,
+and the secrets are redacted as in the verbose output, see RedactionPolicy.  An SDK downloaded by Install is recorded
+once the caller closes the response body, with its size but not its content.
+
+The log is kept in memory, up to MaxEntries entries, and is written to Path by Save or Close; it is not saved
+automatically.
+
+A HARRecorder is safe for concurrent use, and may be shared by several clients.
+*/
+type HARRecorder struct {
+	Path       string // Optional; file written by Save and Close
+	MaxEntries int    // Maximum number of entries kept, the oldest ones being dropped; 0 keeps every entry
+
+	mu      sync.Mutex
+	entries []harEntry
+	saveMu  sync.Mutex // Serializes the saves, so that the last one writes the whole log
+}
+
+// NewHARRecorder returns a recorder keeping the last DEFAULT_HAR_MAX_ENTRIES entries, which Save and Close write
+// to the file path; with an empty path, the log is only kept in memory, see WriteTo.
+func NewHARRecorder(path string) *HARRecorder {
+	return &HARRecorder{Path: path, MaxEntries: DEFAULT_HAR_MAX_ENTRIES}
+}
+
+// WriteTo writes the log as HAR 1.2 JSON.
+func (h *HARRecorder) WriteTo(w io.Writer) (int64, error) {
+	h.mu.Lock()
+	log := harLog{Log: harContent{
+		Version: "1.2",
+		Creator: harCreator{Name: "OpenWhisk-Go-Client", Version: "1.0"},
+		Entries: append([]harEntry{}, h.entries...),
+	}}
+	h.mu.Unlock()
+
+	data, err := json.MarshalIndent(log, "", "  ")
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(append(data, '\n'))
+	return int64(n), err
+}
+
+// Save writes the log to Path.  The file is replaced atomically, and is only readable and writable by its owner.
+func (h *HARRecorder) Save() error {
+	if len(h.Path) == 0 {
+		errStr := wski18n.T("Unable to save the HAR log, because the recorder has no path")
+		return MakeWskError(errors.New(errStr), EXIT_CODE_ERR_GENERAL, DISPLAY_MSG, NO_DISPLAY_USAGE)
+	}
+
+	h.saveMu.Lock()
+	defer h.saveMu.Unlock()
+
+	var buffer bytes.Buffer
+	if _, err := h.WriteTo(&buffer); err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(h.Path), "."+filepath.Base(h.Path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err = tmp.Chmod(HAR_FILE_MODE); err == nil {
+		_, err = tmp.Write(buffer.Bytes())
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), h.Path)
+	}
+	return err
+}
+
+// Close saves the log to Path, if any, once the clients are done.  The recorder may still be used afterwards.
+func (h *HARRecorder) Close() error {
+	if len(h.Path) == 0 {
+		return nil
+	}
+	return h.Save()
+}
+
+func (h *HARRecorder) add(entry harEntry) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.entries = append(h.entries, entry)
+	if h.MaxEntries > 0 && len(h.entries) > h.MaxEntries {
+		h.entries = append(h.entries[:0:0], h.entries[len(h.entries)-h.MaxEntries:]...)
+	}
+}
+
+// HAR 1.2 log, see http://www.softwareishard.com/blog/har-12-spec/
+type harLog struct {
+	Log harContent `json:"log"`
+}
+
+type harContent struct {
+	Version string     `json:"version"`
+	Creator harCreator `json:"creator"`
+	Entries []harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+	Comment         string      `json:"comment,omitempty"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	Content     harBodyContent `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harBodyContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+}
+
+// Durations in milliseconds; -1 when they do not apply
+type harTimings struct {
+	Blocked float64 `json:"blocked"`
+	DNS     float64 `json:"dns"`
+	Connect float64 `json:"connect"`
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+	SSL     float64 `json:"ssl"`
+}
+
+// harTimer collects the timings of a request from its httptrace events.  When the request is retried, the
+// timings of the connection are the ones of the last attempt, and the time before it counts as blocked.
+type harTimer struct {
+	mu                                 sync.Mutex
+	start, dnsStart, dnsDone           time.Time
+	connectStart, connectDone          time.Time
+	tlsStart, tlsDone                  time.Time
+	gotConn, wroteRequest, gotResponse time.Time
+}
+
+func newHARTimer() *harTimer {
+	return &harTimer{start: time.Now()}
+}
+
+// withTrace returns a copy of the request whose events are traced by the timer.
+func (t *harTimer) withTrace(req *http.Request) *http.Request {
+	set := func(at *time.Time) {
+		t.mu.Lock()
+		*at = time.Now()
+		t.mu.Unlock()
+	}
+	trace := &httptrace.ClientTrace{
+		GetConn: func(string) {
+			// A new attempt, whose connection may be reused
+			t.mu.Lock()
+			t.dnsStart, t.dnsDone, t.connectStart, t.connectDone = time.Time{}, time.Time{}, time.Time{}, time.Time{}
+			t.tlsStart, t.tlsDone, t.gotConn, t.wroteRequest = time.Time{}, time.Time{}, time.Time{}, time.Time{}
+			t.gotResponse = time.Time{}
+			t.mu.Unlock()
+		},
+		DNSStart:             func(httptrace.DNSStartInfo) { set(&t.dnsStart) },
+		DNSDone:              func(httptrace.DNSDoneInfo) { set(&t.dnsDone) },
+		ConnectStart:         func(string, string) { set(&t.connectStart) },
+		ConnectDone:          func(string, string, error) { set(&t.connectDone) },
+		TLSHandshakeStart:    func() { set(&t.tlsStart) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { set(&t.tlsDone) },
+		GotConn:              func(httptrace.GotConnInfo) { set(&t.gotConn) },
+		WroteRequest:         func(httptrace.WroteRequestInfo) { set(&t.wroteRequest) },
+		GotFirstResponseByte: func() { set(&t.gotResponse) },
+	}
+	return req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+}
+
+// responded marks the reception of the response headers, for the transports that are not traced.
+func (t *harTimer) responded() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.gotResponse.IsZero() {
+		t.gotResponse = time.Now()
+	}
+}
+
+// timings returns the timings of a request whose response was received at end.
+func (t *harTimer) timings(end time.Time) (float64, harTimings) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	ms := func(from time.Time, to time.Time) float64 {
+		if from.IsZero() || to.IsZero() {
+			return -1
+		}
+		return float64(to.Sub(from)) / float64(time.Millisecond)
+	}
+	timings := harTimings{
+		DNS:     ms(t.dnsStart, t.dnsDone),
+		Connect: ms(t.connectStart, t.connectDone),
+		SSL:     ms(t.tlsStart, t.tlsDone),
+		Receive: ms(t.gotResponse, end),
+	}
+	if timings.Connect >= 0 && timings.SSL >= 0 {
+		// The connect time of HAR includes the TLS handshake
+		timings.Connect = ms(t.connectStart, t.tlsDone)
+	}
+
+	if t.gotConn.IsZero() || t.wroteRequest.IsZero() {
+		// Not traced, e.g. with a custom http.RoundTripper
+		timings.Blocked, timings.Send, timings.Wait = -1, 0, ms(t.start, t.gotResponse)
+	} else {
+		timings.Blocked = ms(t.start, t.gotConn)
+		for _, d := range []float64{timings.DNS, timings.Connect} {
+			if d > 0 {
+				timings.Blocked -= d
+			}
+		}
+		if timings.Blocked < 0 {
+			timings.Blocked = 0
+		}
+		timings.Send = ms(t.gotConn, t.wroteRequest)
+		timings.Wait = ms(t.wroteRequest, t.gotResponse)
+	}
+	// Unlike the others, these timings are required: a request that failed has not waited nor received anything
+	for _, d := range []*float64{&timings.Send, &timings.Wait, &timings.Receive} {
+		if *d < 0 {
+			*d = 0
+		}
+	}
+	return ms(t.start, end), timings
+}
+
+// recordHAR records a request sent by the client, and its response if it was received.  The body of the
+// response is not recorded when data is nil, only its size.
+func (c *Client) recordHAR(timer *harTimer, req *http.Request, reqBody []byte, resp *http.Response, data []byte,
+	size int, secrets []ObfuscateSet, reqErr error) {
+	r := c.redactor()
+	end := time.Now()
+	total, timings := timer.timings(end)
+
+	entry := harEntry{
+		StartedDateTime: timer.start.Format("2006-01-02T15:04:05.000Z07:00"),
+		Time:            total,
+		Request: harRequest{
+			Method:      req.Method,
+			URL:         r.url(req.URL),
+			HTTPVersion: req.Proto,
+			Cookies:     []harNameValue{},
+			Headers:     harHeaders(r.header(req.Header)),
+			QueryString: []harNameValue{},
+			HeadersSize: -1,
+			BodySize:    len(reqBody),
+		},
+		Response: harResponse{
+			Cookies:     []harNameValue{},
+			Headers:     []harNameValue{},
+			HeadersSize: -1,
+			BodySize:    -1,
+		},
+		Timings: timings,
+	}
+	if len(entry.Request.HTTPVersion) == 0 {
+		entry.Request.HTTPVersion = "HTTP/1.1"
+	}
+	query := req.URL.Query()
+	for name := range query {
+		if r.queryParams[name] {
+			query[name] = []string{REDACTED}
+		}
+	}
+	entry.Request.QueryString = harHeaders(http.Header(query))
+	if len(reqBody) > 0 {
+		entry.Request.PostData = &harPostData{MimeType: req.Header.Get("Content-Type"), Text: r.body(reqBody, secrets)}
+	}
+
+	if resp != nil {
+		entry.Response.Status = resp.StatusCode
+		entry.Response.StatusText = http.StatusText(resp.StatusCode)
+		entry.Response.HTTPVersion = resp.Proto
+		entry.Response.Headers = harHeaders(r.header(resp.Header))
+		entry.Response.BodySize = size
+		entry.Response.Content = harBodyContent{Size: size, MimeType: resp.Header.Get("Content-Type")}
+		if data != nil {
+			entry.Response.Content.Text = r.body(data, secrets)
+		}
+	}
+	if reqErr != nil {
+		entry.Comment = reqErr.Error()
+	}
+
+	c.Config.HAR.add(entry)
+}
+
+func harHeaders(header http.Header) []harNameValue {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := []harNameValue{}
+	for _, name := range names {
+		for _, value := range header[name] {
+			pairs = append(pairs, harNameValue{Name: name, Value: value})
+		}
+	}
+	return pairs
+}
+
+// readRequestBody returns the body of the request, from GetBody if possible; otherwise the body is read and
+// replaced with a copy.
+func readRequestBody(req *http.Request) []byte {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil
+	}
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			data, _ := ioutil.ReadAll(body)
+			body.Close()
+			return data
+		}
+	}
+	data, _ := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	req.Body = ioutil.NopCloser(bytes.NewReader(data))
+	return data
+}
+
+// harBody counts the bytes of a response body read by the caller, and records the request once the body is
+// closed.
+type harBody struct {
+	io.ReadCloser
+	size   int
+	once   sync.Once
+	record func(size int)
+}
+
+func (b *harBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.size += n
+	return n, err
+}
+
+func (b *harBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(func() { b.record(b.size) })
+	return err
+}
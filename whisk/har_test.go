@@ -0,0 +1,151 @@
+// +build unit
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package whisk
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func readHAR(t *testing.T, recorder *HARRecorder) harLog {
+	var buffer bytes.Buffer
+	_, err := recorder.WriteTo(&buffer)
+	assert.Nil(t, err)
+	var log harLog
+	assert.Nil(t, json.Unmarshal(buffer.Bytes(), &log))
+	return log
+}
+
+func TestHARRecorder(t *testing.T) {
+	large := strings.Repeat("x", 2000)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=secret")
+		w.Write([]byte(`{"name":"test","annotations":[{"key":"description","value":"` + large + `"}]}`))
+	}))
+	defer server.Close()
+
+	config := GetValidConfigTest()
+	config.HAR = NewHARRecorder("")
+	config.Redaction = &RedactionPolicy{SecretKeys: []string{"password"}}
+	client := newLocalTestClient(t, server, config)
+
+	trigger := &Trigger{Name: "test", Parameters: KeyValueArr{{Key: "password", Value: "hunter2"}}}
+	_, _, err := client.Triggers.Insert(trigger, true)
+	assert.Nil(t, err)
+
+	log := readHAR(t, config.HAR)
+	assert.Equal(t, "1.2", log.Log.Version)
+	assert.Equal(t, 1, len(log.Log.Entries))
+	entry := log.Log.Entries[0]
+
+	assert.Equal(t, "PUT", entry.Request.Method)
+	assert.Equal(t, server.URL+"/api/v1/namespaces/"+FakeNamespace+"/triggers/test?overwrite=true", entry.Request.URL)
+	assert.Contains(t, entry.Request.QueryString, harNameValue{Name: "overwrite", Value: "true"})
+	assert.Contains(t, entry.Request.Headers, harNameValue{Name: "Authorization", Value: REDACTED})
+	assert.Contains(t, entry.Request.PostData.Text, `{"key":"password","value":"******"}`)
+	assert.NotContains(t, entry.Request.PostData.Text, "hunter2")
+
+	assert.Equal(t, http.StatusOK, entry.Response.Status)
+	assert.Contains(t, entry.Response.Headers, harNameValue{Name: "Set-Cookie", Value: REDACTED})
+	assert.Equal(t, "application/json", entry.Response.Content.MimeType)
+	assert.Contains(t, entry.Response.Content.Text, large, "the body is not truncated")
+
+	assert.True(t, entry.Time > 0)
+	assert.True(t, entry.Timings.Send >= 0 && entry.Timings.Wait >= 0 && entry.Timings.Receive >= 0)
+	assert.True(t, entry.Timings.Connect >= 0, "a new connection is made to the server")
+	assert.Equal(t, float64(-1), entry.Timings.SSL)
+}
+
+func TestHARRecorderFailureAndInstall(t *testing.T) {
+	dir, err := ioutil.TempDir("", "har")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "traffic.har")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("sdk content"))
+	}))
+	config := GetValidConfigTest()
+	config.HAR = NewHARRecorder(path)
+	client := newLocalTestClient(t, server, config)
+
+	resp, err := client.Sdks.Install("blackbox.tar.gz")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(readHAR(t, config.HAR).Log.Entries), "the SDK is recorded once it is read")
+	ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	server.Close()
+	_, _, err = client.Triggers.Get("test")
+	assert.NotNil(t, err)
+
+	// The log is only written on request
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+	assert.Nil(t, config.HAR.Close())
+
+	data, err := ioutil.ReadFile(path)
+	assert.Nil(t, err)
+	var log harLog
+	assert.Nil(t, json.Unmarshal(data, &log))
+	assert.Equal(t, 2, len(log.Log.Entries))
+
+	install := log.Log.Entries[0]
+	assert.Equal(t, server.URL+"/blackbox.tar.gz", install.Request.URL)
+	assert.Equal(t, len("sdk content"), install.Response.Content.Size)
+	assert.Equal(t, "", install.Response.Content.Text)
+
+	failed := log.Log.Entries[1]
+	assert.Equal(t, 0, failed.Response.Status)
+	assert.NotEqual(t, "", failed.Comment)
+
+	info, err := os.Stat(path)
+	assert.Nil(t, err)
+	assert.Equal(t, HAR_FILE_MODE, info.Mode().Perm())
+}
+
+func TestHARRecorderSave(t *testing.T) {
+	recorder := NewHARRecorder("")
+	assert.Equal(t, DEFAULT_HAR_MAX_ENTRIES, recorder.MaxEntries)
+	assert.Nil(t, recorder.Close())
+	assert.NotNil(t, recorder.Save(), "there is no path to save to")
+
+	recorder.MaxEntries = 2
+	for _, method := range []string{"GET", "PUT", "DELETE"} {
+		recorder.add(harEntry{Request: harRequest{Method: method}})
+	}
+	entries := readHAR(t, recorder).Log.Entries
+	assert.Equal(t, 2, len(entries))
+	assert.Equal(t, "PUT", entries[0].Request.Method)
+	assert.Equal(t, "DELETE", entries[1].Request.Method)
+
+	recorder.Path = filepath.Join(os.TempDir(), "missing-har-dir", "traffic.har")
+	assert.NotNil(t, recorder.Save(), "the errors are returned")
+	assert.NotNil(t, recorder.Close())
+}
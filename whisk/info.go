@@ -18,6 +18,7 @@
 package whisk
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/apache/openwhisk-client-go/wski18n"
@@ -37,11 +38,15 @@ type InfoService struct {
 }
 
 func (s *InfoService) Get() (*Info, *http.Response, error) {
+	return s.GetContext(context.Background())
+}
+
+func (s *InfoService) GetContext(ctx context.Context) (*Info, *http.Response, error) {
 	// make a request to c.BaseURL / v1
 	urlStr := fmt.Sprintf("%s/%s", s.client.BaseURL.String(), s.client.Config.Version)
 	u, err := url.Parse(urlStr)
 	if err != nil {
-		Debug(DbgError, "url.Parse(%s) error: %s\n", urlStr, err)
+		s.client.debug(DbgError, "url.Parse(%s) error: %s\n", urlStr, err)
 		errStr := wski18n.T("Unable to URL parse '{{.version}}': {{.err}}",
 			map[string]interface{}{"version": urlStr, "err": err})
 		werr := MakeWskError(errors.New(errStr), EXIT_CODE_ERR_GENERAL, DISPLAY_MSG, NO_DISPLAY_USAGE)
@@ -50,18 +55,18 @@ func (s *InfoService) Get() (*Info, *http.Response, error) {
 
 	req, err := http.NewRequest("GET", u.String(), nil)
 	if err != nil {
-		Debug(DbgError, "http.NewRequest(GET, %s) error: %s\n", u.String(), err)
+		s.client.debug(DbgError, "http.NewRequest(GET, %s) error: %s\n", u.String(), err)
 		errStr := wski18n.T("Unable to create HTTP request for GET '{{.url}}': {{.err}}",
 			map[string]interface{}{"url": u.String(), "err": err})
 		werr := MakeWskError(errors.New(errStr), EXIT_CODE_ERR_GENERAL, DISPLAY_MSG, NO_DISPLAY_USAGE)
 		return nil, nil, werr
 	}
 
-	Debug(DbgInfo, "Sending HTTP URL '%s'; req %#v\n", req.URL.String(), req)
+	s.client.debug(DbgInfo, "Sending HTTP URL '%s'\n", s.client.redactedURL(req))
 	info := new(Info)
-	resp, err := s.client.Do(req, &info, ExitWithSuccessOnTimeout)
+	resp, err := s.client.DoContext(ctx, req, &info, ExitWithSuccessOnTimeout)
 	if err != nil {
-		Debug(DbgError, "s.client.Do() error - HTTP req %s; error '%s'\n", req.URL.String(), err)
+		s.client.debug(DbgError, "s.client.Do() error - HTTP req %s; error '%s'\n", s.client.redactedURL(req), err)
 		return nil, nil, err
 	}
 
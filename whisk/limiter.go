@@ -0,0 +1,247 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package whisk
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// On an HTTP 429, the rate of the throttled class is divided by this factor...
+	RATE_LIMIT_DECREASE_FACTOR = 2
+	// ...but not below this fraction of its configured rate
+	RATE_LIMIT_MIN_FRACTION = 1.0 / 16
+	// Every other response restores this fraction of the configured rate
+	RATE_LIMIT_INCREASE_FRACTION = 1.0 / 20
+)
+
+// RateLimitPolicy limits the requests of a client on the client side, to stay within the invocations per minute
+// and concurrent invocations limits of the namespace.  Each class of operations is limited separately.
+//
+// The limits adapt to the HTTP 429 (throttled) responses: the rate of the throttled class is halved, then restored
+// step by step by the next responses, and no request of the class is sent before the time given by the
+// response's Retry-After header, if any.
+type RateLimitPolicy struct {
+	Read   RateLimit // GET and HEAD requests
+	Invoke RateLimit // Action invocations
+	Fire   RateLimit // Trigger fires
+	Write  RateLimit // The other requests: creating, updating and deleting entities, enabling rules, ...
+}
+
+// RateLimit is the limit of a class of operations.  The zero value does not limit the requests.
+type RateLimit struct {
+	Rate        float64 // Sustained number of requests per second, e.g. 600.0/60 for 600 invocations per minute; 0 is unlimited
+	Burst       int     // Number of requests that may be sent at once when the rate allows it.  Default is 1
+	MaxInFlight int     // Maximum number of concurrent requests, which last until their response body is closed; 0 is unlimited
+}
+
+// rateLimiter holds the state of the limits of a client, which is shared by the copies of the client
+type rateLimiter struct {
+	read   *classLimiter
+	invoke *classLimiter
+	fire   *classLimiter
+	write  *classLimiter
+}
+
+func newRateLimiter(policy *RateLimitPolicy) *rateLimiter {
+	if policy == nil {
+		return nil
+	}
+	return &rateLimiter{
+		read:   newClassLimiter("read", policy.Read),
+		invoke: newClassLimiter("invoke", policy.Invoke),
+		fire:   newClassLimiter("fire", policy.Fire),
+		write:  newClassLimiter("write", policy.Write),
+	}
+}
+
+// class returns the limiter of the class of the request: only the POST requests to an action or a trigger are
+// invokes and fires, and the other requests changing an entity are writes.
+func (l *rateLimiter) class(req *http.Request) *classLimiter {
+	if req.Method == "GET" || req.Method == "HEAD" {
+		return l.read
+	}
+	switch activationCollection(req) {
+	case "actions":
+		return l.invoke
+	case "triggers":
+		return l.fire
+	}
+	return l.write
+}
+
+type classLimiter struct {
+	name  string
+	limit RateLimit
+	slots chan struct{} // Semaphore of the requests in flight; nil when unlimited
+
+	mu          sync.Mutex
+	rate        float64 // Current rate, adapted to the throttling
+	tokens      float64
+	last        time.Time
+	pausedUntil time.Time
+}
+
+func newClassLimiter(name string, limit RateLimit) *classLimiter {
+	if limit.Burst <= 0 {
+		limit.Burst = 1
+	}
+	l := &classLimiter{name: name, limit: limit, rate: limit.Rate, tokens: float64(limit.Burst), last: time.Now()}
+	if limit.MaxInFlight > 0 {
+		l.slots = make(chan struct{}, limit.MaxInFlight)
+	}
+	return l
+}
+
+// acquire waits until a request of the class may be sent: first for a token of the rate limit, then for a slot
+// of the requests in flight.  Unless it fails, release must be called once the request is done.  The delays are
+// traced by the client c.
+func (l *classLimiter) acquire(ctx context.Context, c *Client) error {
+	if delay := l.reserve(); delay > 0 {
+		c.debug(DbgInfo, "Delaying the %s request by %v to stay within the rate limit\n", l.name, delay)
+		if err := sleepContext(ctx, delay); err != nil {
+			l.unreserve()
+			return err
+		}
+	}
+
+	if l.slots != nil {
+		select {
+		case l.slots <- struct{}{}:
+		case <-ctx.Done():
+			l.unreserve()
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+func (l *classLimiter) release() {
+	if l.slots != nil {
+		<-l.slots
+	}
+}
+
+// reserve takes a token from the bucket, and returns how long to wait for it.
+func (l *classLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	var delay time.Duration
+	if l.rate > 0 {
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if l.tokens > float64(l.limit.Burst) {
+			l.tokens = float64(l.limit.Burst)
+		}
+		l.last = now
+		l.tokens--
+		if l.tokens < 0 {
+			delay = time.Duration(-l.tokens / l.rate * float64(time.Second))
+		}
+	}
+	if paused := l.pausedUntil.Sub(now); paused > delay {
+		delay = paused
+	}
+	return delay
+}
+
+// unreserve gives back the token of a request that is not sent.
+func (l *classLimiter) unreserve() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.rate > 0 {
+		l.tokens++
+	}
+}
+
+// observe adapts the rate to the response, and returns the new rate when it was reduced.
+func (l *classLimiter) observe(resp *http.Response) (float64, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if resp.StatusCode != http.StatusTooManyRequests {
+		if l.limit.Rate > 0 && l.rate < l.limit.Rate {
+			l.rate += l.limit.Rate * RATE_LIMIT_INCREASE_FRACTION
+			if l.rate > l.limit.Rate {
+				l.rate = l.limit.Rate
+			}
+		}
+		return l.rate, false
+	}
+
+	if delay, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+		if until := time.Now().Add(delay); until.After(l.pausedUntil) {
+			l.pausedUntil = until
+		}
+	}
+	if l.limit.Rate > 0 {
+		l.rate /= RATE_LIMIT_DECREASE_FACTOR
+		if min := l.limit.Rate * RATE_LIMIT_MIN_FRACTION; l.rate < min {
+			l.rate = min
+		}
+	}
+	return l.rate, true
+}
+
+// roundTrip sends the request through the HTTP client, within the client's rate limits.
+func (c *Client) roundTrip(req *http.Request) (*http.Response, error) {
+	if c.limiter == nil {
+		return c.client.Do(req)
+	}
+
+	class := c.limiter.class(req)
+	if err := class.acquire(req.Context(), c); err != nil {
+		c.debug(DbgError, "HTTP %s %s was not sent: %s\n", req.Method, c.redactedURL(req), err)
+		return nil, err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		class.release()
+	} else {
+		if rate, throttled := class.observe(resp); throttled {
+			c.debug(DbgWarn, "HTTP %s %s was throttled; slowing down the %s requests\n", req.Method, c.redactedURL(req), class.name)
+			fields := LogFields{"method": req.Method, "url": c.redactedURL(req), "status": resp.StatusCode, "class": class.name}
+			if rate > 0 {
+				fields["rate"] = rate
+			}
+			c.log(LogWarn, "HTTP request throttled", fields)
+		}
+		// The request is in flight until its response body is closed
+		resp.Body = &limitedBody{ReadCloser: resp.Body, release: class.release}
+	}
+	return resp, err
+}
+
+// limitedBody is a response body that releases the slot of its request when it is closed.
+type limitedBody struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (b *limitedBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
@@ -0,0 +1,204 @@
+// +build unit
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package whisk
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRateLimitClasses(t *testing.T) {
+	limiter := newRateLimiter(&RateLimitPolicy{})
+	classOf := func(method string, path string) string {
+		req, _ := http.NewRequest(method, "https://"+FakeHost+"/api/v1/namespaces/_/"+path, nil)
+		return limiter.class(req).name
+	}
+	assert.Equal(t, "read", classOf("GET", "actions/hello"))
+	assert.Equal(t, "invoke", classOf("POST", "actions/hello?blocking=true"))
+	assert.Equal(t, "invoke", classOf("POST", "actions/pkg/hello"))
+	assert.Equal(t, "fire", classOf("POST", "triggers/ticks"))
+	assert.Equal(t, "write", classOf("PUT", "actions/hello"))
+	assert.Equal(t, "write", classOf("POST", "rules/hello"))
+	assert.Equal(t, "write", classOf("DELETE", "triggers/ticks"))
+	assert.Equal(t, "write", classOf("POST", "triggers/ticks/more"))
+	assert.Equal(t, "write", classOf("POST", "packages/actions"))
+	assert.Equal(t, "write", classOf("POST", "rules/triggers"))
+	assert.Nil(t, newRateLimiter(nil))
+}
+
+func TestRateLimitMaxInFlight(t *testing.T) {
+	var lock sync.Mutex
+	inFlight, maxInFlight := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		lock.Unlock()
+		time.Sleep(20 * time.Millisecond)
+		lock.Lock()
+		inFlight--
+		lock.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"activationId":"f00ba7"}`))
+	}))
+	defer server.Close()
+
+	config := GetValidConfigTest()
+	config.RateLimit = &RateLimitPolicy{Invoke: RateLimit{MaxInFlight: 2}}
+	client := newLocalTestClient(t, server, config)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, err := client.Actions.Invoke("hello", nil, false, false)
+			assert.Nil(t, err)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 2, maxInFlight)
+}
+
+func TestRateLimitSlotHeldUntilBodyClosed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	config := GetValidConfigTest()
+	config.RateLimit = &RateLimitPolicy{Read: RateLimit{MaxInFlight: 1}}
+	client := newLocalTestClient(t, server, config)
+	newRequest := func(ctx context.Context) *http.Request {
+		req, err := http.NewRequest("GET", server.URL, nil)
+		assert.Nil(t, err)
+		return req.WithContext(ctx)
+	}
+
+	resp, err := client.roundTrip(newRequest(context.Background()))
+	assert.Nil(t, err)
+
+	// The response of the first request is not read yet, so the second one waits for its slot
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = client.roundTrip(newRequest(ctx))
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	assert.Nil(t, resp.Body.Close())
+	assert.Nil(t, resp.Body.Close())
+	resp, err = client.roundTrip(newRequest(context.Background()))
+	assert.Nil(t, err)
+	resp.Body.Close()
+	assert.Equal(t, 0, len(client.limiter.read.slots))
+}
+
+func TestRateLimitRate(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	config := GetValidConfigTest()
+	config.RateLimit = &RateLimitPolicy{Fire: RateLimit{Rate: 50, Burst: 2}}
+	client := newLocalTestClient(t, server, config)
+
+	// The burst is sent at once, then one request every 20ms
+	start := time.Now()
+	for i := 0; i < 6; i++ {
+		_, _, err := client.Triggers.Fire("ticks", nil)
+		assert.Nil(t, err)
+	}
+	assert.True(t, time.Since(start) >= 70*time.Millisecond, "requests were not rate limited")
+	assert.Equal(t, 6, requests)
+
+	// Reads are not limited by the fire limit
+	start = time.Now()
+	for i := 0; i < 6; i++ {
+		client.Triggers.Get("ticks")
+	}
+	assert.True(t, time.Since(start) < 70*time.Millisecond, "reads were rate limited")
+
+	// Waiting for the rate limit stops with the context
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	client.Triggers.(TriggerServiceContextInterface).FireContext(ctx, "ticks", nil)
+	client.Triggers.(TriggerServiceContextInterface).FireContext(ctx, "ticks", nil)
+	_, _, err := client.Triggers.(TriggerServiceContextInterface).FireContext(ctx, "ticks", nil)
+	assert.NotNil(t, err)
+}
+
+func TestRateLimitAdaptsToThrottling(t *testing.T) {
+	throttled := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if throttled {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"error":"Too many requests in the last minute (count: 61, allowed: 60).","code":"a1b2c3"}`))
+			return
+		}
+		w.Write([]byte(`{"activationId":"f00ba7"}`))
+	}))
+	defer server.Close()
+
+	logger := &recordingLogger{}
+	config := GetValidConfigTest()
+	config.RateLimit = &RateLimitPolicy{Invoke: RateLimit{Rate: 100}}
+	config.Logger = logger
+	client := newLocalTestClient(t, server, config)
+	invoke := client.limiter.invoke
+
+	_, _, err := client.Actions.Invoke("hello", nil, false, false)
+	assert.NotNil(t, err)
+	assert.Equal(t, 50.0, invoke.rate)
+	throttledEntry := logger.entries[1]
+	assert.Equal(t, "HTTP request throttled", throttledEntry.msg)
+	assert.Equal(t, "invoke", throttledEntry.fields["class"])
+	assert.Equal(t, 50.0, throttledEntry.fields["rate"])
+
+	// No invocation is sent before the Retry-After time, and the rate is restored by the next responses
+	delay := invoke.reserve()
+	invoke.unreserve()
+	assert.True(t, delay > 900*time.Millisecond && delay <= time.Second, "unexpected delay %v", delay)
+	invoke.pausedUntil = time.Time{}
+	throttled = false
+	for i := 0; i < 10; i++ {
+		_, _, err = client.Actions.Invoke("hello", nil, false, false)
+		assert.Nil(t, err)
+	}
+	assert.Equal(t, 100.0, invoke.rate)
+
+	// The rate is not reduced below its minimum
+	throttled = true
+	for i := 0; i < 6; i++ {
+		invoke.observe(&http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}})
+	}
+	assert.Equal(t, 100*RATE_LIMIT_MIN_FRACTION, invoke.rate)
+}
@@ -0,0 +1,202 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package whisk
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type LogLevel int
+
+const (
+	LogDebug LogLevel = iota
+	LogInfo
+	LogWarn
+	LogError
+)
+
+func (level LogLevel) String() string {
+	switch level {
+	case LogDebug:
+		return "debug"
+	case LogInfo:
+		return "info"
+	case LogWarn:
+		return "warn"
+	case LogError:
+		return "error"
+	}
+	return fmt.Sprintf("level(%d)", int(level))
+}
+
+// Structured fields of a log message.  The client sets the following fields on the messages about its requests:
+//
+//	method   - HTTP method of the request
+//	url      - URL of the request
+//	status   - HTTP status code of the response
+//	duration - time.Duration between sending the request and receiving the response
+//	attempt  - Number of the failed attempt, when the request is retried
+//	delay    - time.Duration before the request is retried
+//	error    - Error that made the request fail
+//	class    - Class of operations of a throttled request, see RateLimitPolicy
+//	rate     - Reduced rate of the class of a throttled request, in requests per second
+//	body     - Request or response body, with the secrets redacted, see RedactionPolicy; only logged at LogDebug level
+//	curl     - Equivalent curl command of the request, see CurlCommand; only logged when debug output is enabled
+type LogFields map[string]interface{}
+
+// Logger receives the log messages of a Client, configured with Config.Logger.  The client then sends its debug
+// output, when enabled by Config.Debug or SetDebug, to the Logger as well, instead of stdout.  When no Logger is
+// configured, the client keeps tracing its requests to stdout when verbose or debug output is enabled, see
+// Config.Verbose and Config.Debug for a single client, or SetVerbose and SetDebug for every client.
+type Logger interface {
+	Log(level LogLevel, msg string, fields LogFields)
+}
+
+// NewTextLogger returns a Logger that writes the messages of the given level and above to w, one line per message:
+//
+//	2006-01-02T15:04:05.000Z07:00 info HTTP response method=GET status=200 url=https://...
+func NewTextLogger(w io.Writer, level LogLevel) Logger {
+	return &textLogger{w: w, level: level}
+}
+
+type textLogger struct {
+	mu    sync.Mutex
+	w     io.Writer
+	level LogLevel
+}
+
+func (l *textLogger) Log(level LogLevel, msg string, fields LogFields) {
+	if level < l.level {
+		return
+	}
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var line strings.Builder
+	fmt.Fprintf(&line, "%s %s %s", time.Now().Format("2006-01-02T15:04:05.000Z07:00"), level, msg)
+	for _, key := range keys {
+		fmt.Fprintf(&line, " %s=%v", key, fields[key])
+	}
+	line.WriteString("\n")
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	io.WriteString(l.w, line.String())
+}
+
+// log sends a message to the client's Logger, if one is configured.
+func (c *Client) log(level LogLevel, msg string, fields LogFields) {
+	if c.Config.Logger != nil {
+		c.Config.Logger.Log(level, msg, fields)
+	}
+}
+
+// isDebug returns whether the debug output of the client is enabled, by its Config.Debug or by SetDebug for every
+// client.  A nil client only follows SetDebug.
+func (c *Client) isDebug() bool {
+	return (c != nil && c.Config.Debug) || IsDebug()
+}
+
+// isVerbose returns whether the verbose output of the client is enabled, by its Config.Verbose or by SetVerbose for
+// every client, or along with the debug output.
+func (c *Client) isVerbose() bool {
+	return (c != nil && c.Config.Verbose) || c.isDebug() || IsVerbose()
+}
+
+// verbose prints a message to stdout, as Verbose does, when the verbose output of the client is enabled.
+func (c *Client) verbose(msgFormat string, args ...interface{}) {
+	if c.isVerbose() {
+		fmt.Printf("%v", fmt.Sprintf(msgFormat, args...))
+	}
+}
+
+// debug traces a debug message, as Debug does, to the client's Logger when one is configured, and otherwise to stdout.
+func (c *Client) debug(dl DebugLevel, msgFormat string, args ...interface{}) {
+	if c.isDebug() {
+		c.traceDebug(dl, fmt.Sprintf(msgFormat, args...))
+	}
+}
+
+// debugOf is Client.debug for the services using a ClientInterface, whose debug messages go to stdout unless it is a
+// Client.
+func debugOf(client ClientInterface, dl DebugLevel, msgFormat string, args ...interface{}) {
+	if c, _ := client.(*Client); c.isDebug() {
+		c.traceDebug(dl, fmt.Sprintf(msgFormat, args...))
+	}
+}
+
+// traceDebug sends the debug message of a function calling debug or debugOf to the Logger, at the level matching dl.
+func (c *Client) traceDebug(dl DebugLevel, msg string) {
+	if c == nil || c.Config.Logger == nil {
+		printDebug(3, dl, msg)
+		return
+	}
+
+	level := LogDebug
+	switch dl {
+	case DbgWarn:
+		level = LogWarn
+	case DbgError, DbgFatal:
+		level = LogError
+	}
+	c.Config.Logger.Log(level, strings.TrimSpace(msg), nil)
+}
+
+// logRequest logs the request, reading its body from GetBody so that the request itself is left untouched.
+func (c *Client) logRequest(req *http.Request, secrets []ObfuscateSet) {
+	fields := LogFields{"method": req.Method, "url": c.redactedURL(req)}
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			data, _ := ioutil.ReadAll(body)
+			body.Close()
+			fields["body"] = c.redactor().body(data, secrets)
+		}
+	}
+	c.log(LogDebug, "HTTP request", fields)
+}
+
+// logResponse reads and logs the response, and reloads its body to allow the caller access to the body.
+func (c *Client) logResponse(req *http.Request, resp *http.Response, duration time.Duration, secrets []ObfuscateSet) (*http.Response, []byte, error) {
+	data, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = ioutil.NopCloser(bytes.NewBuffer(data))
+	if err != nil {
+		c.debug(DbgError, "ioutil.ReadAll(resp.Body) error: %s\n", err)
+		c.log(LogError, "HTTP response body could not be read", LogFields{"method": req.Method, "url": c.redactedURL(req),
+			"status": resp.StatusCode, "duration": duration, "error": err})
+		return resp, data, MakeWskError(err, EXIT_CODE_ERR_NETWORK, DISPLAY_MSG, NO_DISPLAY_USAGE)
+	}
+
+	c.log(LogInfo, "HTTP response", LogFields{"method": req.Method, "url": c.redactedURL(req),
+		"status": resp.StatusCode, "duration": duration})
+	c.log(LogDebug, "HTTP response body", LogFields{"method": req.Method, "url": c.redactedURL(req),
+		"status": resp.StatusCode, "body": c.redactor().body(data, secrets)})
+	return resp, data, nil
+}
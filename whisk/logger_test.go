@@ -0,0 +1,229 @@
+// +build unit
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package whisk
+
+import (
+	"bytes"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type logEntry struct {
+	level  LogLevel
+	msg    string
+	fields LogFields
+}
+
+type recordingLogger struct {
+	entries []logEntry
+}
+
+func (l *recordingLogger) Log(level LogLevel, msg string, fields LogFields) {
+	l.entries = append(l.entries, logEntry{level, msg, fields})
+}
+
+func TestClientLogger(t *testing.T) {
+	var bodies []string
+	server := newFlakyServer(1, http.StatusServiceUnavailable, &bodies)
+	defer server.Close()
+
+	logger := &recordingLogger{}
+	config := GetValidConfigTest()
+	config.Logger = logger
+	config.Retry = &RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond}
+	client := newLocalTestClient(t, server, config)
+
+	_, _, err := client.Triggers.Insert(&Trigger{Name: "test"}, true)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(logger.entries))
+
+	request := logger.entries[0]
+	assert.Equal(t, LogDebug, request.level)
+	assert.Equal(t, "PUT", request.fields["method"])
+	assert.Contains(t, request.fields["url"], "/triggers/test")
+	assert.Contains(t, request.fields["body"], `"name":"test"`)
+
+	retry := logger.entries[1]
+	assert.Equal(t, LogWarn, retry.level)
+	assert.Equal(t, http.StatusServiceUnavailable, retry.fields["status"])
+	assert.Equal(t, 1, retry.fields["attempt"])
+
+	response := logger.entries[2]
+	assert.Equal(t, LogInfo, response.level)
+	assert.Equal(t, "HTTP response", response.msg)
+	assert.Equal(t, http.StatusOK, response.fields["status"])
+	assert.IsType(t, time.Duration(0), response.fields["duration"])
+
+	body := logger.entries[3]
+	assert.Equal(t, LogDebug, body.level)
+	assert.Contains(t, body.fields["body"], `"namespace":"my_namespace"`)
+}
+
+func TestClientLoggerDebugOutput(t *testing.T) {
+	var bodies []string
+	server := newFlakyServer(1, http.StatusServiceUnavailable, &bodies)
+	defer server.Close()
+
+	debug := IsDebug()
+	SetDebug(true)
+	defer SetDebug(debug)
+
+	logger := &recordingLogger{}
+	config := GetValidConfigTest()
+	config.Logger = logger
+	config.Retry = &RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond}
+	client := newLocalTestClient(t, server, config)
+
+	output := captureStdout(t, func() {
+		_, _, err := client.Triggers.Get("test")
+		assert.Nil(t, err)
+	})
+	assert.Equal(t, "", output, "the debug output goes to the logger")
+
+	var curl, retried bool
+	for _, entry := range logger.entries {
+		assert.NotContains(t, entry.msg, "\n")
+		if entry.msg == "Equivalent curl command" {
+			curl = true
+			assert.Equal(t, LogDebug, entry.level)
+			assert.True(t, strings.HasPrefix(entry.fields["curl"].(string), "curl -X GET "))
+		}
+		if strings.Contains(entry.msg, "attempt 1 returned status 503") {
+			retried = true
+			assert.Equal(t, LogWarn, entry.level)
+		}
+	}
+	assert.True(t, curl)
+	assert.True(t, retried)
+
+	// Without a logger, the debug output still names the calling function
+	client = newRetryTestClient(t, server, &RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond})
+	output = captureStdout(t, func() {
+		_, _, err := client.Triggers.Get("test")
+		assert.Nil(t, err)
+	})
+	assert.Contains(t, output, "(*Client).addAuthHeader]")
+	assert.Contains(t, output, ").parseSuccessResponse]")
+}
+
+func TestClientLoggerHelperDebugOutput(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"name":"test","namespace":"my_namespace"}]`))
+	}))
+	defer server.Close()
+
+	debug := IsDebug()
+	SetDebug(true)
+	defer SetDebug(debug)
+
+	logger := &recordingLogger{}
+	config := GetValidConfigTest()
+	config.Logger = logger
+	config.Credentials = NewRefreshingCredentials(func(ctx context.Context) (string, time.Time, error) {
+		return "token", time.Time{}, nil
+	})
+	config.RateLimit = &RateLimitPolicy{Read: RateLimit{Rate: 20, Burst: 1}}
+	client := newLocalTestClient(t, server, config)
+
+	// The pagers, the route options, the rate limiter and the credential providers trace to the logger too
+	output := captureStdout(t, func() {
+		triggers, err := client.Triggers.(TriggerServiceContextInterface).ListAll(&TriggerListOptions{Limit: 5}).All()
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(triggers))
+		_, _, err = client.Triggers.List(&TriggerListOptions{Limit: 5})
+		assert.Nil(t, err)
+	})
+	assert.Equal(t, "", output, "the debug output goes to the logger")
+
+	msgs := []string{}
+	for _, entry := range logger.entries {
+		msgs = append(msgs, entry.msg)
+	}
+	text := strings.Join(msgs, "\n")
+	assert.Contains(t, text, "Got 1 entities for list page")
+	assert.Contains(t, text, "Adding options to route")
+	assert.Contains(t, text, "Refreshing the bearer token")
+	assert.Contains(t, text, "to stay within the rate limit")
+}
+
+func TestClientConfigDebugOutput(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"name":"test","namespace":"my_namespace"}`))
+	}))
+	defer server.Close()
+
+	debug, verbose := IsDebug(), IsVerbose()
+	SetDebug(false)
+	SetVerbose(false)
+	defer func() {
+		SetDebug(debug)
+		SetVerbose(verbose)
+	}()
+
+	config := GetValidConfigTest()
+	config.Debug = true
+	debugClient := newLocalTestClient(t, server, config)
+	config = GetValidConfigTest()
+	config.Verbose = true
+	verboseClient := newLocalTestClient(t, server, config)
+	quietClient := newLocalTestClient(t, server, nil)
+
+	// The output of one client does not enable the output of the others
+	output := captureStdout(t, func() {
+		_, _, err := quietClient.Triggers.Get("test")
+		assert.Nil(t, err)
+	})
+	assert.Equal(t, "", output)
+
+	output = captureStdout(t, func() {
+		_, _, err := verboseClient.Triggers.Get("test")
+		assert.Nil(t, err)
+	})
+	assert.Contains(t, output, "REQUEST:")
+	assert.Contains(t, output, "Got response with code 200")
+	assert.NotContains(t, output, "Equivalent curl command")
+
+	output = captureStdout(t, func() {
+		_, _, err := debugClient.Triggers.Get("test")
+		assert.Nil(t, err)
+	})
+	assert.Contains(t, output, "REQUEST:")
+	assert.Contains(t, output, "Equivalent curl command")
+	assert.Contains(t, output, "(*Client).addAuthHeader]")
+}
+
+func TestTextLogger(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := NewTextLogger(&buffer, LogInfo)
+
+	logger.Log(LogDebug, "hidden", nil)
+	logger.Log(LogWarn, "HTTP response", LogFields{"status": 429, "method": "GET"})
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	assert.Equal(t, 1, len(lines))
+	assert.True(t, strings.HasSuffix(lines[0], " warn HTTP response method=GET status=429"), lines[0])
+}
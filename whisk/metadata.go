@@ -0,0 +1,101 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package whisk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Header of the transaction id of an OpenWhisk request.  The controller uses the value of the request's header,
+// if any, as the transaction id, and returns the transaction id in the response's header.
+const TRANSACTION_ID_HEADER = "X-Request-ID"
+
+// CallMetadata describes an HTTP call made by Client.Do.  It is available from the WskError of a failed call, and
+// from any call made with a context given by WithCallMetadata.
+type CallMetadata struct {
+	TransactionId string        // Transaction id of the call, from the X-Request-ID response header or the code of an error response
+	CorrelationId string        // Correlation id sent with the request, see WithCorrelationId
+	Method        string        // HTTP method of the request
+	URL           string        // URL of the request, with the secrets redacted, see RedactionPolicy
+	StatusCode    int           // HTTP status code of the response; 0 when no response was received
+	Duration      time.Duration // Time from sending the request to reading the response, including the retries
+	Retries       int           // Number of times the request was sent again, see RetryPolicy
+}
+
+type callMetadataKey struct{}
+
+type correlationIdKey struct{}
+
+// WithCallMetadata returns a copy of ctx with which the calls of the client fill md, e.g.
+//
+//	var md whisk.CallMetadata
+//	_, _, err := client.Actions.GetContext(whisk.WithCallMetadata(ctx, &md), "hello", false)
+//	fmt.Println(md.TransactionId, md.Duration)
+//
+// When the context is used for several calls, md describes the last one.  The calls should not be concurrent.
+func WithCallMetadata(ctx context.Context, md *CallMetadata) context.Context {
+	return context.WithValue(ctx, callMetadataKey{}, md)
+}
+
+// WithCorrelationId returns a copy of ctx with which the requests of the client are sent with the correlation id
+// in their X-Request-ID header.  OpenWhisk then uses it as the transaction id of the requests, which correlates
+// them with the caller's own logs.
+func WithCorrelationId(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIdKey{}, id)
+}
+
+func correlationId(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIdKey{}).(string)
+	return id
+}
+
+// withCorrelationId returns a copy of the request with its correlation id header set, if the context has one.
+func withCorrelationId(req *http.Request) *http.Request {
+	id := correlationId(req.Context())
+	if len(id) == 0 {
+		return req
+	}
+	// Clone the header, which is shared with the caller's request
+	req = req.Clone(req.Context())
+	req.Header.Set(TRANSACTION_ID_HEADER, id)
+	return req
+}
+
+// complete sets the outcome of the call, attaches the metadata to the error of the call, and reports it to the
+// caller of the call.
+func (md *CallMetadata) complete(ctx context.Context, resp *http.Response, err error) {
+	if resp != nil {
+		md.StatusCode = resp.StatusCode
+		md.TransactionId = resp.Header.Get(TRANSACTION_ID_HEADER)
+	}
+	var errResp *ErrorResponse
+	if len(md.TransactionId) == 0 && errors.As(err, &errResp) && errResp.Code != nil && *errResp.Code != nil {
+		md.TransactionId = fmt.Sprint(*errResp.Code)
+	}
+
+	if werr, ok := err.(*WskError); ok && werr.Metadata == nil {
+		werr.Metadata = md
+	}
+	if target, ok := ctx.Value(callMetadataKey{}).(*CallMetadata); ok && target != nil {
+		*target = *md
+	}
+}
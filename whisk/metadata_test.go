@@ -0,0 +1,105 @@
+// +build unit
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package whisk
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCallMetadata(t *testing.T) {
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.Header.Get(TRANSACTION_ID_HEADER))
+		w.Header().Set(TRANSACTION_ID_HEADER, "tid-"+r.Header.Get(TRANSACTION_ID_HEADER))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"name":"test","namespace":"my_namespace"}`))
+	}))
+	defer server.Close()
+	client := newLocalTestClient(t, server, nil)
+
+	var md CallMetadata
+	ctx := WithCallMetadata(WithCorrelationId(context.Background(), "my-id"), &md)
+	_, _, err := client.Triggers.(TriggerServiceContextInterface).GetContext(ctx, "test")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"my-id"}, received)
+	assert.Equal(t, "tid-my-id", md.TransactionId)
+	assert.Equal(t, "my-id", md.CorrelationId)
+	assert.Equal(t, "GET", md.Method)
+	assert.Equal(t, server.URL+"/api/v1/namespaces/my_namespace/triggers/test", md.URL)
+	assert.Equal(t, http.StatusOK, md.StatusCode)
+	assert.True(t, md.Duration > 0)
+	assert.Equal(t, 0, md.Retries)
+
+	// Without a correlation id, no header is sent
+	_, _, err = client.Triggers.(TriggerServiceContextInterface).GetContext(WithCallMetadata(context.Background(), &md), "test")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"my-id", ""}, received)
+	assert.Equal(t, "tid-", md.TransactionId)
+	assert.Equal(t, "", md.CorrelationId)
+}
+
+func TestCallMetadataOfErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":"The requested resource does not exist.","code":"4XcsDSiVGyh8OM1N8nNQ0WcGJqzGnIG3"}`))
+	}))
+	defer server.Close()
+	client := newLocalTestClient(t, server, nil)
+
+	_, _, err := client.Triggers.Get("test")
+	var werr *WskError
+	assert.True(t, errors.As(err, &werr))
+	assert.NotNil(t, werr.Metadata)
+	assert.Equal(t, "4XcsDSiVGyh8OM1N8nNQ0WcGJqzGnIG3", werr.Metadata.TransactionId)
+	assert.Equal(t, http.StatusNotFound, werr.Metadata.StatusCode)
+	assert.Equal(t, "GET", werr.Metadata.Method)
+
+	// The metadata is kept when the error is wrapped
+	wrapped := MakeWskErrorFromWskError(errors.New("wrapped"), err, EXIT_CODE_ERR_GENERAL)
+	assert.Equal(t, werr.Metadata, wrapped.Metadata)
+
+	// A request that was not answered has metadata as well
+	server.Close()
+	_, _, err = client.Triggers.Get("test")
+	assert.True(t, errors.As(err, &werr))
+	assert.NotNil(t, werr.Metadata)
+	assert.Equal(t, 0, werr.Metadata.StatusCode)
+	assert.Equal(t, "", werr.Metadata.TransactionId)
+}
+
+func TestCallMetadataRetries(t *testing.T) {
+	var bodies []string
+	server := newFlakyServer(2, http.StatusServiceUnavailable, &bodies)
+	defer server.Close()
+	client := newRetryTestClient(t, server, &RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond})
+
+	var md CallMetadata
+	_, _, err := client.Triggers.(TriggerServiceContextInterface).GetContext(WithCallMetadata(context.Background(), &md), "test")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, md.Retries)
+	assert.Equal(t, http.StatusOK, md.StatusCode)
+}
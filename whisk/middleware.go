@@ -0,0 +1,61 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package whisk
+
+import (
+	"context"
+	"net/http"
+)
+
+// Handler performs one call of the client: it sends the request and decodes the response body into v.
+// v is nil when the caller does not expect a response body.
+type Handler func(ctx context.Context, req *http.Request, v interface{}) (*http.Response, error)
+
+// Middleware wraps the Handler of every call of a Client.  A middleware can modify the request before calling
+// next, inspect the response, the decoded v and the error after next returned, or not call next at all, e.g.
+//
+//	func(next whisk.Handler) whisk.Handler {
+//		return func(ctx context.Context, req *http.Request, v interface{}) (*http.Response, error) {
+//			req.Header.Set("X-Signature", sign(req))
+//			return next(ctx, req, v)
+//		}
+//	}
+//
+// The request has its authentication, from Config.Credentials as well, and additional headers set, but its body
+// may not be rewindable: a middleware that reads the body has to replace it.
+type Middleware func(next Handler) Handler
+
+// Use appends middleware to the client's chain.  Middleware run in the order they were added: the first one
+// added is the outermost, which sees the request first and the response last.  Retries happen inside the
+// chain, so a middleware sees one call however many attempts it took.
+func (c *Client) Use(middleware ...Middleware) {
+	c.middlewareLock.Lock()
+	defer c.middlewareLock.Unlock()
+	c.middleware = append(c.middleware, middleware...)
+}
+
+// chain wraps handler with the client's middleware.
+func (c *Client) chain(handler Handler) Handler {
+	c.middlewareLock.RLock()
+	defer c.middlewareLock.RUnlock()
+
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		handler = c.middleware[i](handler)
+	}
+	return handler
+}
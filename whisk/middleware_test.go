@@ -0,0 +1,92 @@
+// +build unit
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package whisk
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// tracingMiddleware records when it sees the request and the response in calls
+func tracingMiddleware(name string, calls *[]string) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req *http.Request, v interface{}) (*http.Response, error) {
+			*calls = append(*calls, name+" request")
+			resp, err := next(ctx, req, v)
+			*calls = append(*calls, name+" response")
+			return resp, err
+		}
+	}
+}
+
+func TestMiddlewareChain(t *testing.T) {
+	var headers []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = append(headers, r.Header.Get("X-Test"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"name":"test","namespace":"my_namespace"}`))
+	}))
+	defer server.Close()
+	client := newLocalTestClient(t, server, nil)
+
+	var calls []string
+	var decoded *Trigger
+	client.Use(tracingMiddleware("outer", &calls), tracingMiddleware("inner", &calls))
+	client.Use(func(next Handler) Handler {
+		return func(ctx context.Context, req *http.Request, v interface{}) (*http.Response, error) {
+			req.Header.Set("X-Test", "injected")
+			resp, err := next(ctx, req, v)
+			decoded = *v.(**Trigger)
+			return resp, err
+		}
+	})
+
+	trigger, _, err := client.Triggers.Get("test")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"outer request", "inner request", "inner response", "outer response"}, calls)
+	assert.Equal(t, []string{"injected"}, headers)
+	assert.Equal(t, trigger, decoded)
+}
+
+func TestMiddlewareFaultInjection(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+	client := newLocalTestClient(t, server, nil)
+
+	fault := errors.New("injected fault")
+	client.Use(func(next Handler) Handler {
+		return func(ctx context.Context, req *http.Request, v interface{}) (*http.Response, error) {
+			return nil, fault
+		}
+	})
+
+	_, _, err := client.Actions.Get("test", false)
+	assert.Equal(t, fault, err)
+	_, err = client.Sdks.Install("blackbox.tar.gz")
+	assert.Equal(t, fault, err)
+	assert.Equal(t, 0, requests)
+}
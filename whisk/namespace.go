@@ -18,6 +18,7 @@
 package whisk
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/apache/openwhisk-client-go/wski18n"
@@ -62,14 +63,17 @@ func (namespace Namespace) ToSummaryRowString() string {
 
 // get a list of available namespaces
 func (s *NamespaceService) List() ([]Namespace, *http.Response, error) {
+	return s.ListContext(context.Background())
+}
+
+func (s *NamespaceService) ListContext(ctx context.Context) ([]Namespace, *http.Response, error) {
 	// make a request to c.BaseURL / namespaces
 
 	// Create the request against the namespaces resource
-	s.client.Config.Namespace = ""
-	route := ""
-	req, err := s.client.NewRequest("GET", route, nil, IncludeNamespaceInUrl)
+	route := "namespaces"
+	req, err := s.client.NewRequest("GET", route, nil, DoNotIncludeNamespaceInUrl)
 	if err != nil {
-		Debug(DbgError, "s.client.NewRequest(GET) error: %s\n", err)
+		s.client.debug(DbgError, "s.client.NewRequest(GET) error: %s\n", err)
 		errStr := wski18n.T("Unable to create HTTP request for GET: {{.err}}",
 			map[string]interface{}{"err": err})
 		werr := MakeWskErrorFromWskError(errors.New(errStr), err, EXIT_CODE_ERR_GENERAL, DISPLAY_MSG, NO_DISPLAY_USAGE)
@@ -77,9 +81,9 @@ func (s *NamespaceService) List() ([]Namespace, *http.Response, error) {
 	}
 
 	var namespaceNames []string
-	resp, err := s.client.Do(req, &namespaceNames, ExitWithSuccessOnTimeout)
+	resp, err := s.client.DoContext(ctx, req, &namespaceNames, ExitWithSuccessOnTimeout)
 	if err != nil {
-		Debug(DbgError, "s.client.Do() error - HTTP req %s; error '%s'\n", req.URL.String(), err)
+		s.client.debug(DbgError, "s.client.Do() error - HTTP req %s; error '%s'\n", s.client.redactedURL(req), err)
 		return nil, resp, err
 	}
 
@@ -91,6 +95,6 @@ func (s *NamespaceService) List() ([]Namespace, *http.Response, error) {
 		namespaces = append(namespaces, ns)
 	}
 
-	Debug(DbgInfo, "Returning []namespaces: %#v\n", namespaces)
+	s.client.debug(DbgInfo, "Returning []namespaces: %#v\n", namespaces)
 	return namespaces, resp, nil
 }
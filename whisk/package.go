@@ -18,6 +18,7 @@
 package whisk
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/apache/openwhisk-client-go/wski18n"
@@ -126,10 +127,14 @@ func (xPackage Package) ToSummaryRowString() string {
 }
 
 func (s *PackageService) List(options *PackageListOptions) ([]Package, *http.Response, error) {
+	return s.ListContext(context.Background(), options)
+}
+
+func (s *PackageService) ListContext(ctx context.Context, options *PackageListOptions) ([]Package, *http.Response, error) {
 	route := fmt.Sprintf("packages")
-	routeUrl, err := addRouteOptions(route, options)
+	routeUrl, err := addRouteOptions(s.client, route, options)
 	if err != nil {
-		Debug(DbgError, "addRouteOptions(%s, %#v) error: '%s'\n", route, options, err)
+		s.client.debug(DbgError, "addRouteOptions(%s, %#v) error: '%s'\n", route, options, err)
 		errStr := wski18n.T("Unable to build request URL: {{.err}}", map[string]interface{}{"err": err})
 		werr := MakeWskErrorFromWskError(errors.New(errStr), err, EXIT_CODE_ERR_GENERAL, DISPLAY_MSG, NO_DISPLAY_USAGE)
 		return nil, nil, werr
@@ -137,7 +142,7 @@ func (s *PackageService) List(options *PackageListOptions) ([]Package, *http.Res
 
 	req, err := s.client.NewRequestUrl("GET", routeUrl, nil, IncludeNamespaceInUrl, AppendOpenWhiskPathPrefix, EncodeBodyAsJson, AuthRequired)
 	if err != nil {
-		Debug(DbgError, "http.NewRequestUrl(GET, %s, nil, IncludeNamespaceInUrl, AppendOpenWhiskPathPrefix, EncodeBodyAsJson, AuthRequired); error: '%s'\n", route, err)
+		s.client.debug(DbgError, "http.NewRequestUrl(GET, %s, nil, IncludeNamespaceInUrl, AppendOpenWhiskPathPrefix, EncodeBodyAsJson, AuthRequired); error: '%s'\n", route, err)
 		errStr := wski18n.T("Unable to create GET HTTP request for '{{.route}}': {{.err}}",
 			map[string]interface{}{"route": route, "err": err})
 		werr := MakeWskErrorFromWskError(errors.New(errStr), err, EXIT_CODE_ERR_GENERAL, DISPLAY_MSG, NO_DISPLAY_USAGE)
@@ -145,9 +150,9 @@ func (s *PackageService) List(options *PackageListOptions) ([]Package, *http.Res
 	}
 
 	var packages []Package
-	resp, err := s.client.Do(req, &packages, ExitWithSuccessOnTimeout)
+	resp, err := s.client.DoContext(ctx, req, &packages, ExitWithSuccessOnTimeout)
 	if err != nil {
-		Debug(DbgError, "s.client.Do() error - HTTP req %s; error '%s'\n", req.URL.String(), err)
+		s.client.debug(DbgError, "s.client.Do() error - HTTP req %s; error '%s'\n", s.client.redactedURL(req), err)
 		return nil, resp, err
 	}
 
@@ -156,6 +161,10 @@ func (s *PackageService) List(options *PackageListOptions) ([]Package, *http.Res
 }
 
 func (s *PackageService) Get(packageName string) (*Package, *http.Response, error) {
+	return s.GetContext(context.Background(), packageName)
+}
+
+func (s *PackageService) GetContext(ctx context.Context, packageName string) (*Package, *http.Response, error) {
 	// Encode resource name as a path (with no query params) before inserting it into the URI
 	// This way any '?' chars in the name won't be treated as the beginning of the query params
 	packageName = (&url.URL{Path: packageName}).String()
@@ -163,7 +172,7 @@ func (s *PackageService) Get(packageName string) (*Package, *http.Response, erro
 
 	req, err := s.client.NewRequest("GET", route, nil, IncludeNamespaceInUrl)
 	if err != nil {
-		Debug(DbgError, "http.NewRequest(GET, %s); error: '%s'\n", route, err)
+		s.client.debug(DbgError, "http.NewRequest(GET, %s); error: '%s'\n", route, err)
 		errStr := wski18n.T("Unable to create GET HTTP request for '{{.route}}': {{.err}}",
 			map[string]interface{}{"route": route, "err": err})
 		werr := MakeWskErrorFromWskError(errors.New(errStr), err, EXIT_CODE_ERR_GENERAL, DISPLAY_MSG, NO_DISPLAY_USAGE)
@@ -171,9 +180,9 @@ func (s *PackageService) Get(packageName string) (*Package, *http.Response, erro
 	}
 
 	p := new(Package)
-	resp, err := s.client.Do(req, &p, ExitWithSuccessOnTimeout)
+	resp, err := s.client.DoContext(ctx, req, &p, ExitWithSuccessOnTimeout)
 	if err != nil {
-		Debug(DbgError, "s.client.Do() error - HTTP req %s; error: '%s'\n", req.URL.String(), err)
+		s.client.debug(DbgError, "s.client.Do() error - HTTP req %s; error: '%s'\n", s.client.redactedURL(req), err)
 		return nil, resp, err
 	}
 
@@ -182,6 +191,10 @@ func (s *PackageService) Get(packageName string) (*Package, *http.Response, erro
 }
 
 func (s *PackageService) Insert(x_package PackageInterface, overwrite bool) (*Package, *http.Response, error) {
+	return s.InsertContext(context.Background(), x_package, overwrite)
+}
+
+func (s *PackageService) InsertContext(ctx context.Context, x_package PackageInterface, overwrite bool) (*Package, *http.Response, error) {
 	// Encode resource name as a path (with no query params) before inserting it into the URI
 	// This way any '?' chars in the name won't be treated as the beginning of the query params
 	packageName := (&url.URL{Path: x_package.GetName()}).String()
@@ -189,7 +202,7 @@ func (s *PackageService) Insert(x_package PackageInterface, overwrite bool) (*Pa
 
 	req, err := s.client.NewRequest("PUT", route, x_package, IncludeNamespaceInUrl)
 	if err != nil {
-		Debug(DbgError, "http.NewRequest(PUT, %s); error: '%s'\n", route, err)
+		s.client.debug(DbgError, "http.NewRequest(PUT, %s); error: '%s'\n", route, err)
 		errStr := wski18n.T("Unable to create PUT HTTP request for '{{.route}}': {{.err}}",
 			map[string]interface{}{"route": route, "err": err})
 		werr := MakeWskErrorFromWskError(errors.New(errStr), err, EXIT_CODE_ERR_GENERAL, DISPLAY_MSG, NO_DISPLAY_USAGE)
@@ -197,9 +210,9 @@ func (s *PackageService) Insert(x_package PackageInterface, overwrite bool) (*Pa
 	}
 
 	p := new(Package)
-	resp, err := s.client.Do(req, &p, ExitWithSuccessOnTimeout)
+	resp, err := s.client.DoContext(ctx, req, &p, ExitWithSuccessOnTimeout)
 	if err != nil {
-		Debug(DbgError, "s.client.Do() error - HTTP req %s; error: '%s'\n", req.URL.String(), err)
+		s.client.debug(DbgError, "s.client.Do() error - HTTP req %s; error: '%s'\n", s.client.redactedURL(req), err)
 		return nil, resp, err
 	}
 
@@ -207,6 +220,10 @@ func (s *PackageService) Insert(x_package PackageInterface, overwrite bool) (*Pa
 }
 
 func (s *PackageService) Delete(packageName string) (*http.Response, error) {
+	return s.DeleteContext(context.Background(), packageName)
+}
+
+func (s *PackageService) DeleteContext(ctx context.Context, packageName string) (*http.Response, error) {
 	// Encode resource name as a path (with no query params) before inserting it into the URI
 	// This way any '?' chars in the name won't be treated as the beginning of the query params
 	packageName = (&url.URL{Path: packageName}).String()
@@ -214,16 +231,16 @@ func (s *PackageService) Delete(packageName string) (*http.Response, error) {
 
 	req, err := s.client.NewRequest("DELETE", route, nil, IncludeNamespaceInUrl)
 	if err != nil {
-		Debug(DbgError, "http.NewRequest(DELETE, %s); error: '%s'\n", route, err)
+		s.client.debug(DbgError, "http.NewRequest(DELETE, %s); error: '%s'\n", route, err)
 		errStr := wski18n.T("Unable to create DELETE HTTP request for '{{.route}}': {{.err}}",
 			map[string]interface{}{"route": route, "err": err})
 		werr := MakeWskErrorFromWskError(errors.New(errStr), err, EXIT_CODE_ERR_GENERAL, DISPLAY_MSG, NO_DISPLAY_USAGE)
 		return nil, werr
 	}
 
-	resp, err := s.client.Do(req, nil, ExitWithSuccessOnTimeout)
+	resp, err := s.client.DoContext(ctx, req, nil, ExitWithSuccessOnTimeout)
 	if err != nil {
-		Debug(DbgError, "s.client.Do() error - HTTP req %s; error: '%s'\n", req.URL.String(), err)
+		s.client.debug(DbgError, "s.client.Do() error - HTTP req %s; error: '%s'\n", s.client.redactedURL(req), err)
 		return resp, err
 	}
 
@@ -231,11 +248,15 @@ func (s *PackageService) Delete(packageName string) (*http.Response, error) {
 }
 
 func (s *PackageService) Refresh() (*BindingUpdates, *http.Response, error) {
+	return s.RefreshContext(context.Background())
+}
+
+func (s *PackageService) RefreshContext(ctx context.Context) (*BindingUpdates, *http.Response, error) {
 	route := "packages/refresh"
 
 	req, err := s.client.NewRequest("POST", route, nil, IncludeNamespaceInUrl)
 	if err != nil {
-		Debug(DbgError, "http.NewRequest(POST, %s); error: '%s'\n", route, err)
+		s.client.debug(DbgError, "http.NewRequest(POST, %s); error: '%s'\n", route, err)
 		errStr := wski18n.T("Unable to create POST HTTP request for '{{.route}}': {{.err}}",
 			map[string]interface{}{"route": route, "err": err})
 		werr := MakeWskErrorFromWskError(errors.New(errStr), err, EXIT_CODE_ERR_GENERAL, DISPLAY_MSG, NO_DISPLAY_USAGE)
@@ -243,9 +264,9 @@ func (s *PackageService) Refresh() (*BindingUpdates, *http.Response, error) {
 	}
 
 	updates := &BindingUpdates{}
-	resp, err := s.client.Do(req, updates, ExitWithSuccessOnTimeout)
+	resp, err := s.client.DoContext(ctx, req, updates, ExitWithSuccessOnTimeout)
 	if err != nil {
-		Debug(DbgError, "s.client.Do() error - HTTP req %s; error: '%s'\n", req.URL.String(), err)
+		s.client.debug(DbgError, "s.client.Do() error - HTTP req %s; error: '%s'\n", s.client.redactedURL(req), err)
 		return nil, resp, err
 	}
 
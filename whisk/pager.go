@@ -0,0 +1,319 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package whisk
+
+import (
+	"context"
+)
+
+// The controller never returns more than MaxListLimit entities for a single list request
+const MaxListLimit = 200
+
+// listPager walks a list endpoint page by page, advancing Skip.  The Limit option of the list is the maximum
+// number of entities returned in total, 0 for all of them; the page size is set with SetPageSize.
+// Iteration stops at the first short page, when Limit entities were returned, on the first error, or when the
+// caller stops calling Next.
+type listPager struct {
+	client    ClientInterface // Client whose Logger traces the pages
+	ctx       context.Context
+	pageSize  int
+	capped    bool // Whether the entities are limited to remaining
+	remaining int
+	skip      int
+	index     int
+	size      int
+	done      bool
+	err       error
+	fetch     func(ctx context.Context, limit int, skip int) (int, error)
+}
+
+func newListPager(client ClientInterface, ctx context.Context, limit int, skip int, fetch func(context.Context, int, int) (int, error)) listPager {
+	return listPager{client: client, ctx: ctx, pageSize: MaxListLimit, capped: limit > 0, remaining: limit, skip: skip, fetch: fetch}
+}
+
+// SetPageSize sets the number of entities requested per page, MaxListLimit by default and at most.  It must be
+// called before the first call to Next.
+func (p *listPager) SetPageSize(size int) {
+	if size <= 0 || size > MaxListLimit {
+		size = MaxListLimit
+	}
+	p.pageSize = size
+}
+
+// Next advances to the next entity, fetching the next page when the current one is exhausted.
+// It returns false when there are no more entities or an error occurred; check Err afterwards.
+// Breaking out of the loop early is safe, no resources are held between pages.
+func (p *listPager) Next() bool {
+	if p.err != nil {
+		return false
+	}
+
+	p.index++
+	if p.index < p.size {
+		return true
+	}
+	if p.done {
+		return false
+	}
+	if err := p.ctx.Err(); err != nil {
+		p.err = err
+		return false
+	}
+
+	limit := p.pageSize
+	if p.capped && p.remaining < limit {
+		limit = p.remaining
+	}
+	n, err := p.fetch(p.ctx, limit, p.skip)
+	if err != nil {
+		debugOf(p.client, DbgError, "List page (limit %d, skip %d) error: %s\n", limit, p.skip, err)
+		p.err = err
+		return false
+	}
+	debugOf(p.client, DbgInfo, "Got %d entities for list page (limit %d, skip %d)\n", n, limit, p.skip)
+
+	p.skip += n
+	p.index = 0
+	p.size = n
+	p.remaining -= n
+	if n < limit || (p.capped && p.remaining <= 0) {
+		p.done = true
+	}
+
+	return n > 0
+}
+
+// Err returns the error, if any, that stopped the iteration.
+func (p *listPager) Err() error {
+	return p.err
+}
+
+//////////////////
+// Action Pager //
+//////////////////
+
+type ActionPager struct {
+	listPager
+	page []Action
+}
+
+func (s *ActionService) ListAll(packageName string, options *ActionListOptions) *ActionPager {
+	return s.ListAllContext(context.Background(), packageName, options)
+}
+
+func (s *ActionService) ListAllContext(ctx context.Context, packageName string, options *ActionListOptions) *ActionPager {
+	var opts ActionListOptions
+	if options != nil {
+		opts = *options
+	}
+
+	p := &ActionPager{}
+	p.listPager = newListPager(s.client, ctx, opts.Limit, opts.Skip, func(ctx context.Context, limit int, skip int) (int, error) {
+		opts.Limit, opts.Skip = limit, skip
+		actions, _, err := s.ListContext(ctx, packageName, &opts)
+		p.page = actions
+		return len(actions), err
+	})
+	return p
+}
+
+// Action returns the current action.  Only valid after Next returned true.
+func (p *ActionPager) Action() Action {
+	return p.page[p.index]
+}
+
+// All collects the remaining actions.
+func (p *ActionPager) All() ([]Action, error) {
+	var actions []Action
+	for p.Next() {
+		actions = append(actions, p.Action())
+	}
+	return actions, p.Err()
+}
+
+///////////////////
+// Trigger Pager //
+///////////////////
+
+type TriggerPager struct {
+	listPager
+	page []Trigger
+}
+
+func (s *TriggerService) ListAll(options *TriggerListOptions) *TriggerPager {
+	return s.ListAllContext(context.Background(), options)
+}
+
+func (s *TriggerService) ListAllContext(ctx context.Context, options *TriggerListOptions) *TriggerPager {
+	var opts TriggerListOptions
+	if options != nil {
+		opts = *options
+	}
+
+	p := &TriggerPager{}
+	p.listPager = newListPager(s.client, ctx, opts.Limit, opts.Skip, func(ctx context.Context, limit int, skip int) (int, error) {
+		opts.Limit, opts.Skip = limit, skip
+		triggers, _, err := s.ListContext(ctx, &opts)
+		p.page = triggers
+		return len(triggers), err
+	})
+	return p
+}
+
+// Trigger returns the current trigger.  Only valid after Next returned true.
+func (p *TriggerPager) Trigger() Trigger {
+	return p.page[p.index]
+}
+
+// All collects the remaining triggers.
+func (p *TriggerPager) All() ([]Trigger, error) {
+	var triggers []Trigger
+	for p.Next() {
+		triggers = append(triggers, p.Trigger())
+	}
+	return triggers, p.Err()
+}
+
+////////////////
+// Rule Pager //
+////////////////
+
+type RulePager struct {
+	listPager
+	page []Rule
+}
+
+func (s *RuleService) ListAll(options *RuleListOptions) *RulePager {
+	return s.ListAllContext(context.Background(), options)
+}
+
+func (s *RuleService) ListAllContext(ctx context.Context, options *RuleListOptions) *RulePager {
+	var opts RuleListOptions
+	if options != nil {
+		opts = *options
+	}
+
+	p := &RulePager{}
+	p.listPager = newListPager(s.client, ctx, opts.Limit, opts.Skip, func(ctx context.Context, limit int, skip int) (int, error) {
+		opts.Limit, opts.Skip = limit, skip
+		rules, _, err := s.ListContext(ctx, &opts)
+		p.page = rules
+		return len(rules), err
+	})
+	return p
+}
+
+// Rule returns the current rule.  Only valid after Next returned true.
+func (p *RulePager) Rule() Rule {
+	return p.page[p.index]
+}
+
+// All collects the remaining rules.
+func (p *RulePager) All() ([]Rule, error) {
+	var rules []Rule
+	for p.Next() {
+		rules = append(rules, p.Rule())
+	}
+	return rules, p.Err()
+}
+
+///////////////////
+// Package Pager //
+///////////////////
+
+type PackagePager struct {
+	listPager
+	page []Package
+}
+
+func (s *PackageService) ListAll(options *PackageListOptions) *PackagePager {
+	return s.ListAllContext(context.Background(), options)
+}
+
+func (s *PackageService) ListAllContext(ctx context.Context, options *PackageListOptions) *PackagePager {
+	var opts PackageListOptions
+	if options != nil {
+		opts = *options
+	}
+
+	p := &PackagePager{}
+	p.listPager = newListPager(s.client, ctx, opts.Limit, opts.Skip, func(ctx context.Context, limit int, skip int) (int, error) {
+		opts.Limit, opts.Skip = limit, skip
+		packages, _, err := s.ListContext(ctx, &opts)
+		p.page = packages
+		return len(packages), err
+	})
+	return p
+}
+
+// Package returns the current package.  Only valid after Next returned true.
+func (p *PackagePager) Package() Package {
+	return p.page[p.index]
+}
+
+// All collects the remaining packages.
+func (p *PackagePager) All() ([]Package, error) {
+	var packages []Package
+	for p.Next() {
+		packages = append(packages, p.Package())
+	}
+	return packages, p.Err()
+}
+
+//////////////////////
+// Activation Pager //
+//////////////////////
+
+type ActivationPager struct {
+	listPager
+	page []Activation
+}
+
+func (s *ActivationService) ListAll(options *ActivationListOptions) *ActivationPager {
+	return s.ListAllContext(context.Background(), options)
+}
+
+func (s *ActivationService) ListAllContext(ctx context.Context, options *ActivationListOptions) *ActivationPager {
+	var opts ActivationListOptions
+	if options != nil {
+		opts = *options
+	}
+
+	p := &ActivationPager{}
+	p.listPager = newListPager(s.client, ctx, opts.Limit, opts.Skip, func(ctx context.Context, limit int, skip int) (int, error) {
+		opts.Limit, opts.Skip = limit, skip
+		activations, _, err := s.ListContext(ctx, &opts)
+		p.page = activations
+		return len(activations), err
+	})
+	return p
+}
+
+// Activation returns the current activation.  Only valid after Next returned true.
+func (p *ActivationPager) Activation() Activation {
+	return p.page[p.index]
+}
+
+// All collects the remaining activations.
+func (p *ActivationPager) All() ([]Activation, error) {
+	var activations []Activation
+	for p.Next() {
+		activations = append(activations, p.Activation())
+	}
+	return activations, p.Err()
+}
@@ -0,0 +1,134 @@
+// +build unit
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package whisk
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+// newListServer serves `total` entities from any list endpoint, honoring the limit and skip query parameters.
+// The limit of every request received is recorded in limits.
+func newListServer(total int, limits *[]int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		skip, _ := strconv.Atoi(r.URL.Query().Get("skip"))
+		*limits = append(*limits, limit)
+
+		entities := []map[string]interface{}{}
+		for i := skip; i < total && i < skip+limit; i++ {
+			entities = append(entities, map[string]interface{}{
+				"name":         fmt.Sprintf("entity%d", i),
+				"namespace":    "my_namespace",
+				"activationId": fmt.Sprintf("id%d", i),
+			})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(entities)
+	}))
+}
+
+func TestActionPagerAll(t *testing.T) {
+	var limits []int
+	server := newListServer(25, &limits)
+	defer server.Close()
+	client := newLocalTestClient(t, server, nil)
+
+	pager := client.Actions.ListAll("", nil)
+	pager.SetPageSize(10)
+	actions, err := pager.All()
+	assert.Nil(t, err)
+	assert.Equal(t, 25, len(actions))
+	assert.Equal(t, "entity0", actions[0].Name)
+	assert.Equal(t, "entity24", actions[24].Name)
+	assert.Equal(t, []int{10, 10, 10}, limits)
+}
+
+func TestPagerDefaultPageSize(t *testing.T) {
+	var limits []int
+	server := newListServer(5, &limits)
+	defer server.Close()
+	client := newLocalTestClient(t, server, nil)
+
+	triggers, err := client.Triggers.(TriggerServiceContextInterface).ListAll(&TriggerListOptions{Limit: 1000}).All()
+	assert.Nil(t, err)
+	assert.Equal(t, 5, len(triggers))
+	assert.Equal(t, []int{MaxListLimit}, limits)
+}
+
+func TestPagerLimit(t *testing.T) {
+	var limits []int
+	server := newListServer(25, &limits)
+	defer server.Close()
+	client := newLocalTestClient(t, server, nil)
+
+	// Limit caps the number of entities returned, not the page size
+	pager := client.Packages.ListAll(&PackageListOptions{Limit: 12})
+	pager.SetPageSize(5)
+	packages, err := pager.All()
+	assert.Nil(t, err)
+	assert.Equal(t, 12, len(packages))
+	assert.Equal(t, "entity11", packages[11].Name)
+	assert.Equal(t, []int{5, 5, 2}, limits)
+
+	limits = nil
+	rules, err := client.Rules.ListAll(&RuleListOptions{Limit: 10}).All()
+	assert.Nil(t, err)
+	assert.Equal(t, 10, len(rules))
+	assert.Equal(t, []int{10}, limits)
+}
+
+func TestPagerEarlyTermination(t *testing.T) {
+	var limits []int
+	server := newListServer(100, &limits)
+	defer server.Close()
+	client := newLocalTestClient(t, server, nil)
+
+	pager := client.Activations.ListAll(&ActivationListOptions{Skip: 3})
+	pager.SetPageSize(5)
+	var ids []string
+	for pager.Next() {
+		ids = append(ids, pager.Activation().ActivationID)
+		if len(ids) == 7 {
+			break
+		}
+	}
+	assert.Nil(t, pager.Err())
+	assert.Equal(t, "id3", ids[0])
+	assert.Equal(t, "id9", ids[6])
+	assert.Equal(t, 2, len(limits))
+}
+
+func TestPagerStopsOnError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	client := newLocalTestClient(t, server, nil)
+
+	rules, err := client.Rules.ListAll(nil).All()
+	assert.NotNil(t, err)
+	assert.Equal(t, 0, len(rules))
+}
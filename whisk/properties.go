@@ -0,0 +1,331 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package whisk
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+/*
+PropertiesReader parses properties files, such as .wskprops and whisk.properties.  The syntax is the one of Java
+properties files:
+
+	# Comment lines start with '#' or '!'
+	KEY=value
+	KEY: value
+	KEY value
+	KEY=a long value \
+	    continued on the next line
+	KEY=escapes: \t \n \r \f \\ \= \: \# \u00e9
+
+with the following additions:
+
+  - [name] lines start the section of a named profile, see GetPropsFromWskpropsProfile
+  - Trailing whitespace is removed from values; with Escapes, escape it ("\ ") to keep it
+  - With Escapes, the backslashes start escapes and line continuations.  Without it, they are part of the key or
+    value, as they always were in .wskprops files, e.g. in CERT=C:\Users\me\cert.pem.
+  - With Quotes, a value may be quoted: "..." supports the escapes, '...' is taken literally.  Without it, the
+    quotes are part of the value, as they always were in .wskprops files.
+  - With Interpolate, ${NAME} in a value is replaced with the environment variable NAME; with Escapes, escape the
+    '$' ("\${") to keep it literally.  Single-quoted values are not interpolated.
+
+A malformed line is skipped, and the properties of the other lines are still read; the first one is reported as a
+*PropertiesError, with the line number.
+*/
+type PropertiesReader struct {
+	Escapes     bool
+	Quotes      bool
+	Interpolate bool
+	Getenv      func(key string) string // Environment lookup used for interpolation.  Default is os.Getenv
+}
+
+// PropertiesError reports a syntax error in a properties file.
+type PropertiesError struct {
+	Path string // Path of the file, if known
+	Line int    // Number of the (first) line of the malformed property, from 1
+	Msg  string
+}
+
+func (e *PropertiesError) Error() string {
+	if len(e.Path) == 0 {
+		return fmt.Sprintf("line %d: %s", e.Line, e.Msg)
+	}
+	return fmt.Sprintf("%s:%d: %s", e.Path, e.Line, e.Msg)
+}
+
+// ReadSections reads the properties of a file by section, as ReadPropsSections does.
+func (r PropertiesReader) ReadSections(path string) (map[string]map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	sections, err := r.ParseSections(file)
+	if propErr, ok := err.(*PropertiesError); ok {
+		propErr.Path = path
+		Debug(DbgError, "Invalid properties file: %s\n", propErr)
+		return sections, MakeWskError(propErr, EXIT_CODE_ERR_GENERAL, DISPLAY_MSG, NO_DISPLAY_USAGE)
+	}
+	return sections, err
+}
+
+// ParseSections parses properties by section.  The top-level properties, before the first [name] section header,
+// are returned as the "" section, which is always present.  The malformed lines are skipped, as are the properties
+// under a malformed section header, so that they cannot be mistaken for the ones of another section; the first
+// malformed line is returned as a *PropertiesError, along with the properties of the other lines.
+func (r PropertiesReader) ParseSections(in io.Reader) (map[string]map[string]string, error) {
+	props := map[string]string{}
+	sections := map[string]map[string]string{"": props}
+	var firstErr *PropertiesError
+	report := func(line int, msg string) {
+		if firstErr == nil {
+			firstErr = &PropertiesError{Line: line, Msg: msg}
+		}
+	}
+
+	scanner := bufio.NewScanner(in)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		first := lineNumber
+		line := strings.TrimLeft(scanner.Text(), " \t\f")
+
+		if len(line) == 0 || line[0] == '#' || line[0] == '!' {
+			continue
+		}
+
+		if line[0] == '[' {
+			name, ok := parseSectionHeader(line)
+			if !ok || len(name) == 0 {
+				if !ok {
+					report(first, "unterminated section header")
+				} else {
+					report(first, "empty section name")
+				}
+				props = map[string]string{}
+				continue
+			}
+			if _, ok := sections[name]; !ok {
+				sections[name] = map[string]string{}
+			}
+			props = sections[name]
+			continue
+		}
+
+		// Join the continuation lines
+		for r.continuesLine(line) {
+			line = line[:len(line)-1]
+			if !scanner.Scan() {
+				break
+			}
+			lineNumber++
+			line += strings.TrimLeft(scanner.Text(), " \t\f")
+		}
+
+		key, value, err := r.parseProperty(line)
+		if err != nil {
+			report(first, err.Error())
+			continue
+		}
+		props[key] = value
+	}
+
+	if err := scanner.Err(); err != nil {
+		return sections, err
+	}
+	if firstErr != nil {
+		return sections, firstErr
+	}
+	return sections, nil
+}
+
+// continuesLine returns true when, with Escapes, the line ends with an odd number of backslashes.
+func (r PropertiesReader) continuesLine(line string) bool {
+	if !r.Escapes {
+		return false
+	}
+	backslashes := 0
+	for i := len(line) - 1; i >= 0 && line[i] == '\\'; i-- {
+		backslashes++
+	}
+	return backslashes%2 == 1
+}
+
+// parseProperty parses a logical line, without its leading whitespace, into a key and a value.
+func (r PropertiesReader) parseProperty(line string) (string, string, error) {
+	end := r.keyEnd(line)
+	key, _, err := r.unescape(line[:end], false)
+	if err != nil {
+		return "", "", err
+	}
+
+	rest := strings.TrimLeft(line[end:], " \t\f")
+	if len(rest) > 0 && (rest[0] == '=' || rest[0] == ':') {
+		rest = strings.TrimLeft(rest[1:], " \t\f")
+	}
+
+	value, err := r.parseValue(rest)
+	return key, value, err
+}
+
+// keyEnd returns the index of the first unescaped separator of the line: '=', ':' or whitespace.
+func (r PropertiesReader) keyEnd(line string) int {
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			if r.Escapes {
+				i++
+			}
+		case '=', ':', ' ', '\t', '\f':
+			return i
+		}
+	}
+	return len(line)
+}
+
+func (r PropertiesReader) parseValue(raw string) (string, error) {
+	if !r.Quotes || len(raw) == 0 || (raw[0] != '"' && raw[0] != '\'') {
+		value, keep, err := r.unescape(raw, r.Interpolate)
+		if err != nil {
+			return "", err
+		}
+		// Remove the trailing whitespace, unless it is escaped
+		trimmed := strings.TrimRight(value, " \t\f")
+		if len(trimmed) < keep {
+			trimmed = value[:keep]
+		}
+		return trimmed, nil
+	}
+
+	quote := raw[0]
+	closing := -1
+	for i := 1; i < len(raw); i++ {
+		if raw[i] == '\\' && quote == '"' && r.Escapes {
+			i++
+		} else if raw[i] == quote {
+			closing = i
+			break
+		}
+	}
+	if closing < 0 {
+		return "", fmt.Errorf("unterminated quoted value")
+	}
+	if trailing := strings.TrimSpace(raw[closing+1:]); len(trailing) > 0 {
+		return "", fmt.Errorf("unexpected %q after the quoted value", trailing)
+	}
+	if quote == '\'' {
+		return raw[1:closing], nil
+	}
+	value, _, err := r.unescape(raw[1:closing], r.Interpolate)
+	return value, err
+}
+
+// unescape processes the escapes, with Escapes, and, when requested, the ${NAME} references of a key or value.  It
+// also returns the length of the result up to its last escaped character, which must not be trimmed.
+func (r PropertiesReader) unescape(s string, interpolate bool) (string, int, error) {
+	var out strings.Builder
+	keep := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '\\' && r.Escapes && i+1 < len(s):
+			i++
+			switch s[i] {
+			case 't':
+				out.WriteByte('\t')
+			case 'n':
+				out.WriteByte('\n')
+			case 'r':
+				out.WriteByte('\r')
+			case 'f':
+				out.WriteByte('\f')
+			case 'u':
+				if i+5 > len(s) {
+					return "", 0, fmt.Errorf("malformed \\uxxxx escape")
+				}
+				code, err := strconv.ParseUint(s[i+1:i+5], 16, 16)
+				if err != nil {
+					return "", 0, fmt.Errorf("malformed \\uxxxx escape \"\\u%s\"", s[i+1:i+5])
+				}
+				out.WriteRune(rune(code))
+				i += 4
+			default:
+				out.WriteByte(s[i])
+			}
+			keep = out.Len()
+		case c == '$' && interpolate && i+1 < len(s) && s[i+1] == '{':
+			closing := strings.IndexByte(s[i:], '}')
+			if closing < 0 {
+				return "", 0, fmt.Errorf("unterminated \"${\" reference")
+			}
+			name := s[i+2 : i+closing]
+			if len(name) == 0 {
+				return "", 0, fmt.Errorf("empty \"${}\" reference")
+			}
+			out.WriteString(r.getenv(name))
+			keep = out.Len()
+			i += closing
+		default:
+			out.WriteByte(c)
+		}
+	}
+	return out.String(), keep, nil
+}
+
+func (r PropertiesReader) getenv(key string) string {
+	if r.Getenv != nil {
+		return r.Getenv(key)
+	}
+	return os.Getenv(key)
+}
+
+// escapePropertyValue escapes a value so that a PropertiesReader with Escapes reads it back unchanged.
+func escapePropertyValue(value string) string {
+	var out strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case c == '\\':
+			out.WriteString("\\\\")
+		case c == '\n':
+			out.WriteString("\\n")
+		case c == '\r':
+			out.WriteString("\\r")
+		case c == '\t':
+			out.WriteString("\\t")
+		case c == '\f':
+			out.WriteString("\\f")
+		case c == ' ' && (i == 0 || i == len(value)-1):
+			out.WriteString("\\ ")
+		case (c == '"' || c == '\'') && i == 0:
+			out.WriteByte('\\')
+			out.WriteByte(c)
+		case c == '$' && i+1 < len(value) && value[i+1] == '{':
+			out.WriteString("\\$")
+		default:
+			out.WriteByte(c)
+		}
+	}
+	return out.String()
+}
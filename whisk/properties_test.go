@@ -0,0 +1,271 @@
+// +build unit
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package whisk
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestPropertiesReaderSyntax(t *testing.T) {
+	text := strings.Join([]string{
+		"# comment",
+		"! comment=too",
+		"APIHOST=https://openwhisk.example.com/api?a=b&c=d",
+		"  AUTH : uuid:key  ",
+		"NAMESPACE   guest",
+		"EMPTY",
+		"CONTINUED=one, \\",
+		"    two, \\",
+		"\tthree",
+		"ESCAPES=tab\\tnewline\\nunicode\\u00e9 backslash\\\\",
+		"escaped\\=key\\:name=value",
+		"TRAILING=kept\\ ",
+		`QUOTED="  spaced \"value\" "`,
+		`LITERAL='no \escapes ${HOME}'`,
+		"REFERENCE=${HOME}/.wskprops",
+		"",
+		"[dev]",
+		"NAMESPACE=dev",
+	}, "\n")
+
+	sections, err := PropertiesReader{Escapes: true, Quotes: true}.ParseSections(strings.NewReader(text))
+	assert.Nil(t, err)
+	props := sections[""]
+	assert.Equal(t, "https://openwhisk.example.com/api?a=b&c=d", props[APIHOST])
+	assert.Equal(t, "uuid:key", props[AUTH])
+	assert.Equal(t, "guest", props[NAMESPACE])
+	assert.Equal(t, "", props["EMPTY"])
+	assert.Equal(t, "one, two, three", props["CONTINUED"])
+	assert.Equal(t, "tab\tnewline\nunicodeé backslash\\", props["ESCAPES"])
+	assert.Equal(t, "value", props["escaped=key:name"])
+	assert.Equal(t, "kept ", props["TRAILING"])
+	assert.Equal(t, `  spaced "value" `, props["QUOTED"])
+	assert.Equal(t, `no \escapes ${HOME}`, props["LITERAL"])
+	assert.Equal(t, "${HOME}/.wskprops", props["REFERENCE"])
+	assert.Equal(t, 11, len(props))
+	assert.Equal(t, "dev", sections["dev"][NAMESPACE])
+
+	// Interpolation is optional
+	env := map[string]string{"HOME": "/home/guest"}
+	reader := PropertiesReader{Escapes: true, Quotes: true, Interpolate: true, Getenv: func(key string) string { return env[key] }}
+	sections, err = reader.ParseSections(strings.NewReader(text))
+	assert.Nil(t, err)
+	assert.Equal(t, "/home/guest/.wskprops", sections[""]["REFERENCE"])
+	assert.Equal(t, `no \escapes ${HOME}`, sections[""]["LITERAL"])
+
+	sections, err = reader.ParseSections(strings.NewReader(`ESCAPED=\${HOME} ${MISSING}x`))
+	assert.Nil(t, err)
+	assert.Equal(t, "${HOME} x", sections[""]["ESCAPED"])
+}
+
+func TestPropertiesReaderErrors(t *testing.T) {
+	errorLines := map[string]int{
+		"A=1\nB=\\u00zz\nC=3":               2,
+		"A=1\n\n# c\nB=\"unterminated\nC=3": 4,
+		"A=1\nB='quoted' trailing\nC=3":     2,
+		"A=1\\\n  2\nB=${HOME\nC=3":         3,
+	}
+	for text, line := range errorLines {
+		reader := PropertiesReader{Escapes: true, Quotes: true, Interpolate: true}
+		sections, err := reader.ParseSections(strings.NewReader(text))
+		propErr, ok := err.(*PropertiesError)
+		if assert.True(t, ok, text) {
+			assert.Equal(t, line, propErr.Line, text)
+		}
+		// Only the malformed line is skipped
+		assert.Equal(t, 2, len(sections[""]), text)
+		assert.Equal(t, "3", sections[""]["C"], text)
+	}
+
+	// The properties under a malformed section header are skipped, not read into another section
+	sections, err := PropertiesReader{}.ParseSections(strings.NewReader("A=1\n[dev\nB=2\n[prod]\nC=3"))
+	propErr, ok := err.(*PropertiesError)
+	if assert.True(t, ok) {
+		assert.Equal(t, 2, propErr.Line)
+	}
+	assert.Equal(t, map[string]string{"A": "1"}, sections[""])
+	assert.Equal(t, map[string]string{"C": "3"}, sections["prod"])
+
+	// An empty section name does not reopen the top-level properties
+	sections, err = PropertiesReader{}.ParseSections(strings.NewReader("A=1\n[]\nA=2\n[prod]\nC=3"))
+	propErr, ok = err.(*PropertiesError)
+	if assert.True(t, ok) {
+		assert.Equal(t, 2, propErr.Line)
+		assert.Equal(t, "empty section name", propErr.Msg)
+	}
+	assert.Equal(t, map[string]string{"A": "1"}, sections[""])
+	assert.Equal(t, map[string]string{"C": "3"}, sections["prod"])
+
+	path := getCurrentDir() + "/" + DEFAULT_LOCAL_CONFIG
+	CreateFile([]string{APIHOST + "=" + EXPECTED_HOST, AUTH + "=\\u00zz", NAMESPACE + "=guest"}, path)
+	defer DeleteFile(path)
+
+	sections, err = PropertiesReader{Escapes: true}.ReadSections(path)
+	assert.Equal(t, EXPECTED_HOST, sections[""][APIHOST])
+	assert.Equal(t, "guest", sections[""][NAMESPACE])
+	assert.True(t, errors.As(err, &propErr))
+	assert.Equal(t, path, propErr.Path)
+	assert.Equal(t, 2, propErr.Line)
+	assert.Contains(t, err.Error(), path+`:2: malformed \uxxxx escape "\u00zz"`)
+
+	pi := PropertiesImp{
+		OsPackage: FakeOSPackage{
+			StoredValues: map[string]string{
+				HOMEPATH: getCurrentDir(),
+			},
+		},
+		Escapes: true,
+	}
+	// The malformed lines of a .wskprops file are only warnings
+	dep, err := pi.GetPropsFromWskpropsProfile("", "")
+	assert.Nil(t, err)
+	assert.Equal(t, EXPECTED_HOST, dep.APIHost)
+	assert.Equal(t, "guest", dep.Namespace)
+	assert.Equal(t, "", dep.AuthKey)
+}
+
+func TestPropertiesReaderLiteralBackslashes(t *testing.T) {
+	// Without Escapes, the backslashes of the existing .wskprops files are part of the values
+	path := getCurrentDir() + "/" + DEFAULT_LOCAL_CONFIG
+	CreateFile([]string{APIHOST + "=" + EXPECTED_HOST, AUTH + "=" + EXPECTED_TEST_AUTH_KEY,
+		CERT + `=c:\users\me\cert.pem`, KEY + `=C:\Users\me\key.pem \`}, path)
+	defer DeleteFile(path)
+
+	props, err := ReadProps(path)
+	assert.Nil(t, err)
+	assert.Equal(t, `c:\users\me\cert.pem`, props[CERT])
+
+	pi := PropertiesImp{
+		OsPackage: FakeOSPackage{
+			StoredValues: map[string]string{
+				HOMEPATH: getCurrentDir(),
+			},
+		},
+	}
+	dep := pi.GetPropsFromWskprops("")
+	assert.Equal(t, EXPECTED_HOST, dep.APIHost)
+	assert.Equal(t, EXPECTED_TEST_AUTH_KEY, dep.AuthKey)
+	assert.Equal(t, `c:\users\me\cert.pem`, dep.Cert)
+	assert.Equal(t, `C:\Users\me\key.pem \`, dep.Key)
+
+	file, err := LoadWskpropsFile(path)
+	assert.Nil(t, err)
+	value, ok := file.Get("", CERT)
+	assert.True(t, ok)
+	assert.Equal(t, `c:\users\me\cert.pem`, value)
+}
+
+func TestPropertiesReaderWhiskProperties(t *testing.T) {
+	// whisk.properties is read as a Java properties file, unlike .wskprops
+	CreateFile([]string{OPENWHISK_HOST + "=192.168.\\", "    9.100", OPENWHISK_PORT + "=4\\u00343",
+		TEST_AUTH_FILE + "=" + TEST_AUTH_FILE_NAME}, OPENWHISK_PROPERTIES)
+	defer DeleteFile(OPENWHISK_PROPERTIES)
+	CreateFile([]string{EXPECTED_TEST_AUTH_KEY}, TEST_AUTH_FILE_NAME)
+	defer DeleteFile(TEST_AUTH_FILE_NAME)
+
+	pi := PropertiesImp{
+		OsPackage: FakeOSPackage{
+			StoredValues: map[string]string{
+				OPENWHISK_HOME: getCurrentDir(),
+			},
+		},
+	}
+	dep := pi.GetPropsFromWhiskProperties()
+	assert.Equal(t, EXPECTED_OPENWHISK_HOST, dep.APIHost)
+	assert.Equal(t, EXPECTED_TEST_AUTH_KEY, dep.AuthKey)
+
+	props, err := PropertiesReader{Escapes: true}.ReadSections(OPENWHISK_PROPERTIES)
+	assert.Nil(t, err)
+	assert.Equal(t, EXPECTED_OPENWHISK_PORT, props[""][OPENWHISK_PORT])
+}
+
+func TestPropertiesReaderLegacyValues(t *testing.T) {
+	// Without Quotes, the values of the existing .wskprops files are read as they always were
+	legacy := map[string]string{
+		`AUTH="uuid:key"`:                 `"uuid:key"`,
+		`NAMESPACE='guest'`:               `'guest'`,
+		`APIHOST="localhost" # comment`:   `"localhost" # comment`,
+		`CERT="unterminated`:              `"unterminated`,
+		"KEY=/path/to/key   ":             "/path/to/key",
+		"  APIVERSION  =  v1 \t ":         "v1",
+		`APIGW_ACCESS_TOKEN='${TOKEN}'  `: `'${TOKEN}'`,
+	}
+	for line, expected := range legacy {
+		sections, err := PropertiesReader{}.ParseSections(strings.NewReader(line))
+		if assert.Nil(t, err, line) {
+			for _, value := range sections[""] {
+				assert.Equal(t, expected, value, line)
+			}
+		}
+	}
+
+	path := getCurrentDir() + "/" + DEFAULT_LOCAL_CONFIG
+	CreateFile([]string{AUTH + `="` + EXPECTED_TEST_AUTH_KEY + `"`, NAMESPACE + "=guest  "}, path)
+	defer DeleteFile(path)
+
+	props, err := ReadProps(path)
+	assert.Nil(t, err)
+	assert.Equal(t, `"`+EXPECTED_TEST_AUTH_KEY+`"`, props[AUTH])
+	assert.Equal(t, "guest", props[NAMESPACE])
+
+	pi := PropertiesImp{
+		OsPackage: FakeOSPackage{
+			StoredValues: map[string]string{
+				HOMEPATH: getCurrentDir(),
+			},
+		},
+	}
+	dep, err := pi.GetPropsFromWskpropsProfile("", "")
+	assert.Nil(t, err)
+	assert.Equal(t, `"`+EXPECTED_TEST_AUTH_KEY+`"`, dep.AuthKey)
+	pi.Quotes = true
+	dep, err = pi.GetPropsFromWskpropsProfile("", "")
+	assert.Nil(t, err)
+	assert.Equal(t, EXPECTED_TEST_AUTH_KEY, dep.AuthKey)
+}
+
+func TestPropertiesReaderPartialProfile(t *testing.T) {
+	path := getCurrentDir() + "/" + DEFAULT_LOCAL_CONFIG
+	CreateFile([]string{"[prod]", APIHOST + "=" + EXPECTED_HOST, NAMESPACE + "=production", AUTH + "=\\u00zz"}, path)
+	defer DeleteFile(path)
+
+	pi := PropertiesImp{
+		OsPackage: FakeOSPackage{
+			StoredValues: map[string]string{
+				HOMEPATH: getCurrentDir(),
+			},
+		},
+		Escapes: true,
+	}
+	// Only the malformed line of a profile is skipped
+	dep, err := pi.GetPropsFromWskpropsProfile("", "prod")
+	assert.Nil(t, err)
+	assert.Equal(t, "prod", dep.Profile)
+	assert.Equal(t, "production", dep.Namespace)
+	assert.Equal(t, EXPECTED_HOST, dep.APIHost)
+	assert.Equal(t, "", dep.AuthKey)
+
+	profiles, _, err := pi.ListProfiles("")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"prod"}, profiles)
+}
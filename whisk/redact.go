@@ -0,0 +1,248 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package whisk
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/google/go-querystring/query"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Replacement of the redacted values
+const REDACTED = "******"
+
+// RedactionPolicy selects the secrets that are redacted from the verbose output and the log messages of a client,
+// configured with Config.Redaction.  Its rules are applied in addition to the ones of DefaultRedactionPolicy.
+type RedactionPolicy struct {
+	Headers     []string       // Names of the headers whose values are redacted, e.g. "X-Api-Key"
+	QueryParams []string       // Names of the URL query parameters whose values are redacted
+	JSONPaths   []string       // Paths of the JSON body values to redact, e.g. "exec.code"; "*" matches any field or array element
+	SecretKeys  []string       // Keys of the parameters and annotations ({"key": ..., "value": ...}) whose values are redacted; "*" is all of them
+	Obfuscate   []ObfuscateSet // Regular expressions replaced in the bodies, as the ones given to Client.Do
+}
+
+// DefaultRedactionPolicy redacts the credentials sent by the client: the Authorization and cookie headers, the
+// API gateway access token and the auth key of an API's action.  The passwords are obfuscated by DefaultObfuscateArr.
+var DefaultRedactionPolicy = RedactionPolicy{
+	Headers:     []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"},
+	QueryParams: []string{"accesstoken"},
+	JSONPaths:   []string{"accesstoken", "apidoc.action.authkey"},
+}
+
+// RedactBody returns the body with the secrets of DefaultRedactionPolicy and of policy, which may be nil, redacted
+// as in the verbose output of a client, e.g. to store a recorded request.
+func RedactBody(body []byte, policy *RedactionPolicy) string {
+	return newRedactor(policy).body(body, nil)
+}
+
+// redactor applies the rules of DefaultRedactionPolicy and of a client's policy
+type redactor struct {
+	headers     map[string]bool // By canonical header name
+	queryParams map[string]bool
+	jsonPaths   [][]string
+	secretKeys  map[string]bool
+	obfuscate   []ObfuscateSet
+}
+
+func newRedactor(policy *RedactionPolicy) *redactor {
+	r := &redactor{headers: map[string]bool{}, queryParams: map[string]bool{}, secretKeys: map[string]bool{}}
+	for _, p := range []*RedactionPolicy{&DefaultRedactionPolicy, policy} {
+		if p == nil {
+			continue
+		}
+		for _, name := range p.Headers {
+			r.headers[http.CanonicalHeaderKey(name)] = true
+		}
+		for _, name := range p.QueryParams {
+			r.queryParams[name] = true
+		}
+		for _, path := range p.JSONPaths {
+			r.jsonPaths = append(r.jsonPaths, strings.Split(path, "."))
+		}
+		for _, key := range p.SecretKeys {
+			r.secretKeys[key] = true
+		}
+		r.obfuscate = append(r.obfuscate, p.Obfuscate...)
+	}
+	return r
+}
+
+// RedactionPolicy returns the policy of the client, Config.Redaction, which may be nil.
+func (c *Client) RedactionPolicy() *RedactionPolicy {
+	return c.Config.Redaction
+}
+
+// redactor returns the redactor of the client's policy.
+func (c *Client) redactor() *redactor {
+	return newRedactor(c.RedactionPolicy())
+}
+
+// redactorOf is Client.redactor for the services using a ClientInterface: the policy is the one of a
+// ClientContextInterface, and a ClientInterface without one only has the rules of DefaultRedactionPolicy.
+func redactorOf(client ClientInterface) *redactor {
+	if c, ok := client.(ClientContextInterface); ok {
+		return newRedactor(c.RedactionPolicy())
+	}
+	return newRedactor(nil)
+}
+
+// redactedURL returns the URL of the request, with the secret query parameters redacted, for the log messages.
+func (c *Client) redactedURL(req *http.Request) string {
+	return c.redactor().url(req.URL)
+}
+
+// redactURL is Client.redactedURL for the services using a ClientInterface.
+func redactURL(client ClientInterface, req *http.Request) string {
+	return redactorOf(client).url(req.URL)
+}
+
+// redactOptions returns the URL query options of a request, such as ApiListRequestOptions, as a query string with
+// the secret values of the client's policy redacted, for the log and error messages.
+func redactOptions(client ClientInterface, options interface{}) string {
+	values, err := query.Values(options)
+	if err != nil {
+		return fmt.Sprintf("%T", options)
+	}
+	return redactorOf(client).url(&url.URL{RawQuery: values.Encode()})
+}
+
+// header returns a copy of the header with the secret values redacted.
+func (r *redactor) header(header http.Header) http.Header {
+	redacted := make(http.Header, len(header))
+	for name, values := range header {
+		if r.headers[http.CanonicalHeaderKey(name)] {
+			values = []string{REDACTED}
+		}
+		redacted[name] = values
+	}
+	return redacted
+}
+
+func (r *redactor) url(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+	if len(u.RawQuery) == 0 {
+		return u.String()
+	}
+	query := u.Query()
+	changed := false
+	for name := range query {
+		if r.queryParams[name] {
+			query[name] = []string{REDACTED}
+			changed = true
+		}
+	}
+	if !changed {
+		return u.String()
+	}
+	redacted := *u
+	redacted.RawQuery = query.Encode()
+	return redacted.String()
+}
+
+// body returns the body as text, with the secret JSON values redacted and the regular expressions of the policy
+// and the given ones replaced.  A body that is not JSON is only obfuscated with the regular expressions.
+func (r *redactor) body(data []byte, secrets []ObfuscateSet) string {
+	text := string(data)
+
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if (len(r.jsonPaths) > 0 || len(r.secretKeys) > 0) && decoder.Decode(&value) == nil {
+		changed := false
+		for _, path := range r.jsonPaths {
+			changed = redactPath(value, path) || changed
+		}
+		if len(r.secretKeys) > 0 {
+			changed = r.redactKeyValues(value) || changed
+		}
+		if changed {
+			var buffer bytes.Buffer
+			encoder := json.NewEncoder(&buffer)
+			encoder.SetEscapeHTML(false)
+			if encoder.Encode(value) == nil {
+				text = strings.TrimSuffix(buffer.String(), "\n")
+			}
+		}
+	}
+
+	return ObfuscateText(ObfuscateText(text, r.obfuscate), secrets)
+}
+
+// redactPath redacts the values at the path, and returns whether there were any.
+func redactPath(value interface{}, path []string) bool {
+	if len(path) == 0 {
+		return false
+	}
+	changed := false
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for name, field := range v {
+			if path[0] != "*" && path[0] != name {
+				continue
+			}
+			if len(path) == 1 {
+				v[name] = REDACTED
+				changed = true
+			} else {
+				changed = redactPath(field, path[1:]) || changed
+			}
+		}
+	case []interface{}:
+		if path[0] != "*" {
+			return false
+		}
+		for i, element := range v {
+			if len(path) == 1 {
+				v[i] = REDACTED
+				changed = true
+			} else {
+				changed = redactPath(element, path[1:]) || changed
+			}
+		}
+	}
+	return changed
+}
+
+// redactKeyValues redacts the values of the {"key": ..., "value": ...} objects with a secret key, at any depth, and
+// returns whether there were any.
+func (r *redactor) redactKeyValues(value interface{}) bool {
+	changed := false
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if key, ok := v["key"].(string); ok && (r.secretKeys[key] || r.secretKeys["*"]) {
+			if _, ok := v["value"]; ok {
+				v["value"] = REDACTED
+				return true
+			}
+		}
+		for _, field := range v {
+			changed = r.redactKeyValues(field) || changed
+		}
+	case []interface{}:
+		for _, element := range v {
+			changed = r.redactKeyValues(element) || changed
+		}
+	}
+	return changed
+}
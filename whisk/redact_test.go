@@ -0,0 +1,208 @@
+// +build unit
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package whisk
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestRedactHeaders(t *testing.T) {
+	r := newRedactor(&RedactionPolicy{Headers: []string{"x-api-key"}})
+	header := http.Header{}
+	header.Set("Authorization", "Basic c2VjcmV0")
+	header.Set("Cookie", "session=secret")
+	header.Set("X-Api-Key", "secret")
+	header.Set("Content-Type", "application/json")
+
+	redacted := r.header(header)
+	assert.Equal(t, REDACTED, redacted.Get("Authorization"))
+	assert.Equal(t, REDACTED, redacted.Get("Cookie"))
+	assert.Equal(t, REDACTED, redacted.Get("X-Api-Key"))
+	assert.Equal(t, "application/json", redacted.Get("Content-Type"))
+	assert.Equal(t, "Basic c2VjcmV0", header.Get("Authorization"), "the header itself is left untouched")
+}
+
+func TestRedactURL(t *testing.T) {
+	r := newRedactor(nil)
+	u, _ := url.Parse("https://example.com/api/v1/web/ns/apimgmt/getApi.http?accesstoken=secret&spaceguid=guid")
+	assert.Equal(t, "https://example.com/api/v1/web/ns/apimgmt/getApi.http?accesstoken=%2A%2A%2A%2A%2A%2A&spaceguid=guid", r.url(u))
+
+	u, _ = url.Parse("https://example.com/api/v1/namespaces/_/actions?limit=30")
+	assert.Equal(t, "https://example.com/api/v1/namespaces/_/actions?limit=30", r.url(u))
+}
+
+func TestRedactBody(t *testing.T) {
+	r := newRedactor(&RedactionPolicy{
+		JSONPaths:  []string{"exec.code", "limits.*"},
+		SecretKeys: []string{"dbPassword", "apiKey"},
+		Obfuscate:  []ObfuscateSet{{Regex: "token-[0-9]+", Replacement: "token-***"}},
+	})
+
+	body := `{"exec":{"kind":"nodejs:default","code":"main()"},"limits":{"memory":256},` +
+		`"parameters":[{"key":"dbPassword","value":"secret"},{"key":"name","value":"Mike"}],` +
+		`"annotations":[{"key":"apiKey","value":{"id":"secret"}}],"note":"token-42 <b>"}`
+	redacted := r.body([]byte(body), []ObfuscateSet{{Regex: "Mike", Replacement: "M***"}})
+	assert.Equal(t, `{"annotations":[{"key":"apiKey","value":"******"}],"exec":{"code":"******","kind":"nodejs:default"},`+
+		`"limits":{"memory":"******"},"note":"token-*** <b>",`+
+		`"parameters":[{"key":"dbPassword","value":"******"},{"key":"name","value":"M***"}]}`, redacted)
+
+	// The default paths apply at the top level of the body
+	assert.Equal(t, `{"accesstoken":"******","spaceguid":"guid"}`,
+		newRedactor(nil).body([]byte(`{"spaceguid":"guid","accesstoken":"secret"}`), nil))
+	assert.Equal(t, `{"args": {"accesstoken": "kept"}}`, newRedactor(nil).body([]byte(`{"args": {"accesstoken": "kept"}}`), nil),
+		"a body without secrets is left as is")
+
+	// Other bodies are only obfuscated with the regular expressions
+	assert.Equal(t, `"password": "******" token-*** {`, r.body([]byte(`"password": "hunter2" token-7 {`), DefaultObfuscateArr))
+}
+
+func TestRedactClientLog(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"name":"test","parameters":[{"key":"secret","value":"s3cr3t"}]}`))
+	}))
+	defer server.Close()
+
+	logger := &recordingLogger{}
+	config := GetValidConfigTest()
+	config.Logger = logger
+	config.Redaction = &RedactionPolicy{SecretKeys: []string{"secret"}}
+	client := newLocalTestClient(t, server, config)
+
+	trigger := &Trigger{Name: "test", Parameters: KeyValueArr{{Key: "secret", Value: "s3cr3t"}}}
+	_, _, err := client.Triggers.Insert(trigger, true)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(logger.entries))
+	for _, entry := range logger.entries {
+		if body, ok := entry.fields["body"]; ok {
+			assert.NotContains(t, body, "s3cr3t")
+			assert.Contains(t, body, `{"key":"secret","value":"******"}`)
+		}
+	}
+}
+
+// captureStdout returns what f writes to the standard output, where the debug and verbose output goes.
+func captureStdout(t *testing.T, f func()) string {
+	reader, writer, err := os.Pipe()
+	assert.Nil(t, err)
+	stdout := os.Stdout
+	os.Stdout = writer
+	defer func() { os.Stdout = stdout }()
+
+	output := make(chan string)
+	go func() {
+		var buf bytes.Buffer
+		io.Copy(&buf, reader)
+		output <- buf.String()
+	}()
+	f()
+	writer.Close()
+	return <-output
+}
+
+func TestRedactDebugOutput(t *testing.T) {
+	var bodies []string
+	server := newFlakyServer(1, http.StatusServiceUnavailable, &bodies)
+	defer server.Close()
+	client := newRetryTestClient(t, server, &RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond})
+
+	debug, verbose := IsDebug(), IsVerbose()
+	SetDebug(true)
+	SetVerbose(true)
+	defer func() {
+		SetDebug(debug)
+		SetVerbose(verbose)
+	}()
+
+	options := &ApiListRequestOptions{ApiOptions: ApiOptions{AccessToken: "t0k3n-s3cr3t", SpaceGuid: "guid"}}
+	output := captureStdout(t, func() {
+		_, _, err := client.Apis.List(options)
+		assert.Nil(t, err)
+	})
+	assert.Equal(t, 2, len(bodies))
+	assert.Contains(t, output, "accesstoken="+url.QueryEscape(REDACTED))
+	assert.Contains(t, output, "spaceguid=guid")
+	assert.NotContains(t, output, "t0k3n-s3cr3t")
+}
+
+func TestRedactApiDebugOutput(t *testing.T) {
+	// An API whose operation has no x-openwhisk.url fails the validation of the response
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"apis": [{"id": "api-1", "value": {"namespace": "guest", "apidoc": {"paths": {"/hello": {"get": {
+			"operationId": "getHello", "x-openwhisk": {"namespace": "guest", "action": "hello", "package": ""}}}}}}}]}`))
+	}))
+	defer server.Close()
+
+	debug := IsDebug()
+	SetDebug(true)
+	defer SetDebug(debug)
+
+	client := newLocalTestClient(t, server, GetValidConfigTest())
+	options := &ApiListRequestOptions{ApiOptions: ApiOptions{AccessToken: "t0k3n-s3cr3t", SpaceGuid: "guid"}}
+	output := captureStdout(t, func() {
+		_, _, err := client.Apis.List(options)
+		assert.NotNil(t, err)
+	})
+	// The invalid API is named, not printed
+	assert.Contains(t, output, "no x-openwhisk.url stanza in operation get\n")
+	assert.Contains(t, output, "Invalid Api: api-1\n")
+	assert.NotContains(t, output, "t0k3n-s3cr3t")
+
+	assert.Equal(t, "?accesstoken="+url.QueryEscape(REDACTED)+"&limit=0&skip=0&spaceguid=guid", redactOptions(client, options))
+	assert.Equal(t, "string", redactOptions(client, "not options"))
+}
+
+// policyClient is a ClientContextInterface wrapping a Client, with its own redaction policy
+type policyClient struct {
+	*Client
+	policy *RedactionPolicy
+}
+
+func (c *policyClient) RedactionPolicy() *RedactionPolicy {
+	return c.policy
+}
+
+func TestRedactWithClientInterface(t *testing.T) {
+	client, _ := NewClient(http.DefaultClient, GetValidConfigTest())
+	options := &ApiListRequestOptions{ApiOptions: ApiOptions{AccessToken: "t0k3n-s3cr3t", SpaceGuid: "guid"}}
+	redacted := url.QueryEscape(REDACTED)
+
+	// The policy of a ClientContextInterface is applied
+	custom := &policyClient{Client: client, policy: &RedactionPolicy{QueryParams: []string{"spaceguid"}}}
+	assert.Equal(t, "?accesstoken="+redacted+"&limit=0&skip=0&spaceguid="+redacted, redactOptions(custom, options))
+	req, _ := http.NewRequest("GET", "https://example.com/api?spaceguid=guid", nil)
+	assert.Equal(t, "https://example.com/api?spaceguid="+redacted, redactURL(custom, req))
+	u, err := addRouteOptions(custom, "apis", options)
+	assert.Nil(t, err)
+	assert.Equal(t, "guid", u.Query().Get("spaceguid"))
+
+	// A ClientInterface without a policy only has the rules of DefaultRedactionPolicy
+	var plain ClientInterface = struct{ ClientInterface }{client}
+	assert.Equal(t, "?accesstoken="+redacted+"&limit=0&skip=0&spaceguid=guid", redactOptions(plain, options))
+}
@@ -0,0 +1,157 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package whisk
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/apache/openwhisk-client-go/wski18n"
+)
+
+// Sources of the resolved configuration values, in addition to WHISK_PROPERTY and WSKPROP
+const (
+	CONFIG_SOURCE_DEFAULT  = "default"
+	CONFIG_SOURCE_ENV      = "environment"
+	CONFIG_SOURCE_EXPLICIT = "explicit"
+
+	// Prefix of the environment variables overriding the .wskprops properties, e.g. WSK_APIHOST or WSK_AUTH
+	WSK_ENV_PREFIX = "WSK_"
+)
+
+/*
+ConfigResolver resolves the configuration of a client from several layers, each one overriding the previous ones:
+
+	default          - APIVERSION=v1, NAMESPACE=_ and INSECURE=true
+	whisk.properties - the whisk.properties file under the OPENWHISK_HOME directory
+	wsk props        - the .wskprops file, with its selected profile
+	environment      - the WSK_<property> environment variables: WSK_APIHOST, WSK_AUTH, WSK_NAMESPACE, WSK_APIVERSION,
+	                   WSK_CERT, WSK_KEY, WSK_INSECURE, WSK_APIGW_ACCESS_TOKEN and WSK_APIGW_TENANT_ID
+	explicit         - the non-empty fields of Explicit
+
+Empty values do not override anything.
+*/
+type ConfigResolver struct {
+	OsPackage    OSPackage // Lookup of the environment variables.  Default is the process environment
+	WskpropsPath string    // Path of the .wskprops file.  Default is the .wskprops file under the HOME directory
+	Profile      string    // Profile of the .wskprops file.  Default is the one selected by WSK_PROFILE or PROFILE
+	Explicit     Wskprops  // Values set by the program, e.g. from command line flags
+}
+
+// ResolvedConfig is the outcome of ConfigResolver.Resolve.
+type ResolvedConfig struct {
+	Props   Wskprops          // Resolved values.  Props.Source is the source of AUTH, and APIGWSpaceSuid is derived from AUTH
+	Sources map[string]string // Source of each resolved value, by .wskprops property name (APIHOST, AUTH, ...)
+}
+
+// Resolve reads the layers of the configuration.  A file that does not exist is skipped; a malformed .wskprops
+// file, an undefined profile, or an INSECURE value that is not a boolean is an error.  The other resolved values
+// are not validated, see ValidateWskprops.
+func (r ConfigResolver) Resolve() (*ResolvedConfig, error) {
+	if r.OsPackage == nil {
+		r.OsPackage = OSPackageImp{}
+	}
+	pi := PropertiesImp{
+		OsPackage: r.OsPackage,
+	}
+
+	resolved := &ResolvedConfig{
+		Props:   Wskprops{},
+		Sources: map[string]string{},
+	}
+	defaults := GetDefaultWskprops(CONFIG_SOURCE_DEFAULT)
+	resolved.overlay(CONFIG_SOURCE_DEFAULT, wskpropsValues(defaults, nil))
+	resolved.overlay(WHISK_PROPERTY, wskpropsValues(pi.GetPropsFromWhiskProperties(), defaults))
+
+	values, profile, err := pi.readWskprops(r.WskpropsPath, r.Profile)
+	if err != nil {
+		return nil, err
+	}
+	resolved.Props.Profile = profile
+	resolved.overlay(WSKPROP, values)
+
+	env := map[string]string{}
+	for key := range wskpropsFields(&Wskprops{}) {
+		env[key] = r.OsPackage.Getenv(WSK_ENV_PREFIX+key, "")
+	}
+	resolved.overlay(CONFIG_SOURCE_ENV, env)
+
+	resolved.overlay(CONFIG_SOURCE_EXPLICIT, wskpropsValues(&r.Explicit, nil))
+
+	if _, err := strconv.ParseBool(resolved.Props.Insecure); err != nil {
+		errStr := wski18n.T("Invalid {{.key}} value '{{.value}}' from {{.source}}: expected true or false",
+			map[string]interface{}{"key": INSECURE, "value": resolved.Props.Insecure, "source": resolved.Sources[INSECURE]})
+		return nil, MakeWskError(errors.New(errStr), EXIT_CODE_ERR_GENERAL, DISPLAY_MSG, NO_DISPLAY_USAGE)
+	}
+
+	resolved.Props.Source = resolved.Sources[AUTH]
+	if len(resolved.Props.AuthKey) > 0 {
+		resolved.Props.APIGWSpaceSuid = strings.Split(resolved.Props.AuthKey, ":")[0]
+	}
+	return resolved, nil
+}
+
+// Config returns the client configuration of the resolved values.  As with GetDefaultConfig, the server
+// certificates are only verified when the INSECURE property is "false"; by default, Sources[INSECURE] is
+// CONFIG_SOURCE_DEFAULT.
+func (r *ResolvedConfig) Config() *Config {
+	return convertWskpropsToConfig(&r.Props)
+}
+
+// overlay sets the non-empty values of a layer.
+func (r *ResolvedConfig) overlay(source string, values map[string]string) {
+	fields := wskpropsFields(&r.Props)
+	for key, value := range values {
+		if field, ok := fields[key]; ok && len(value) > 0 {
+			*field = value
+			r.Sources[key] = source
+		}
+	}
+}
+
+// wskpropsFields returns the fields of a Wskprops by .wskprops property name.
+func wskpropsFields(dep *Wskprops) map[string]*string {
+	return map[string]*string{
+		APIHOST:            &dep.APIHost,
+		APIVERSION:         &dep.Apiversion,
+		AUTH:               &dep.AuthKey,
+		NAMESPACE:          &dep.Namespace,
+		CERT:               &dep.Cert,
+		INSECURE:           &dep.Insecure,
+		KEY:                &dep.Key,
+		APIGW_ACCESS_TOKEN: &dep.AuthAPIGWKey,
+		APIGW_TENANT_ID:    &dep.APIGWTenantId,
+	}
+}
+
+// wskpropsValues returns the values of a Wskprops by .wskprops property name, except for the ones equal to the
+// defaults.
+func wskpropsValues(dep *Wskprops, defaults *Wskprops) map[string]string {
+	values := map[string]string{}
+	var defaultFields map[string]*string
+	if defaults != nil {
+		defaultFields = wskpropsFields(defaults)
+	}
+	for key, field := range wskpropsFields(dep) {
+		if defaultFields == nil || *field != *defaultFields[key] {
+			values[key] = *field
+		}
+	}
+	return values
+}
@@ -0,0 +1,119 @@
+// +build unit
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package whisk
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestConfigResolver(t *testing.T) {
+	CreateFile([]string{EXPECTED_TEST_AUTH_KEY}, TEST_AUTH_FILE_NAME)
+	defer DeleteFile(TEST_AUTH_FILE_NAME)
+	CreateFile([]string{
+		OPENWHISK_HOST + "=" + EXPECTED_OPENWHISK_HOST,
+		TEST_AUTH_FILE + "=" + TEST_AUTH_FILE_NAME,
+	}, OPENWHISK_PROPERTIES)
+	defer DeleteFile(OPENWHISK_PROPERTIES)
+	CreateFile([]string{
+		APIHOST + "=" + EXPECTED_API_HOST_LOCAL_CONF,
+		NAMESPACE + "=" + EXPECTED_NAMESPACE_LOCAL_CONF,
+		APIGW_TENANT_ID + "=" + EXPECTED_APIGW_TENANT_ID,
+		"",
+		"[prod]",
+		CERT + "=" + EXPECTED_CERT_LOCAL_CONF,
+		INSECURE + "=false",
+	}, DEFAULT_LOCAL_CONFIG)
+	defer DeleteFile(DEFAULT_LOCAL_CONFIG)
+
+	resolver := ConfigResolver{
+		OsPackage: FakeOSPackage{
+			StoredValues: map[string]string{
+				HOMEPATH:                            getCurrentDir(),
+				OPENWHISK_HOME:                      getCurrentDir(),
+				WSK_ENV_PREFIX + NAMESPACE:          EXPECTED_NAMESPACE_WHISK,
+				WSK_ENV_PREFIX + APIGW_ACCESS_TOKEN: EXPECTED_AUTH_API_KEY,
+				WSK_ENV_PREFIX + KEY:                EXPECTED_KEY_WHISK,
+			},
+		},
+		Profile:  "prod",
+		Explicit: Wskprops{Key: EXPECTED_KEY},
+	}
+
+	resolved, err := resolver.Resolve()
+	assert.Nil(t, err)
+	assert.Equal(t, EXPECTED_API_HOST_LOCAL_CONF, resolved.Props.APIHost)
+	assert.Equal(t, EXPECTED_TEST_AUTH_KEY, resolved.Props.AuthKey)
+	assert.Equal(t, EXPECTED_API_GW_SPACE_SUID, resolved.Props.APIGWSpaceSuid)
+	assert.Equal(t, EXPECTED_NAMESPACE_WHISK, resolved.Props.Namespace)
+	assert.Equal(t, EXPECTED_AUTH_API_KEY, resolved.Props.AuthAPIGWKey)
+	assert.Equal(t, EXPECTED_APIGW_TENANT_ID, resolved.Props.APIGWTenantId)
+	assert.Equal(t, EXPECTED_CERT_LOCAL_CONF, resolved.Props.Cert)
+	assert.Equal(t, EXPECTED_KEY, resolved.Props.Key)
+	assert.Equal(t, DEFAULT_VERSION, resolved.Props.Apiversion)
+	assert.Equal(t, "prod", resolved.Props.Profile)
+	assert.Equal(t, WHISK_PROPERTY, resolved.Props.Source)
+
+	assert.Equal(t, map[string]string{
+		APIHOST:            WSKPROP,
+		APIVERSION:         CONFIG_SOURCE_DEFAULT,
+		AUTH:               WHISK_PROPERTY,
+		NAMESPACE:          CONFIG_SOURCE_ENV,
+		CERT:               WSKPROP,
+		INSECURE:           WSKPROP,
+		KEY:                CONFIG_SOURCE_EXPLICIT,
+		APIGW_ACCESS_TOKEN: CONFIG_SOURCE_ENV,
+		APIGW_TENANT_ID:    WSKPROP,
+	}, resolved.Sources)
+
+	config := resolved.Config()
+	assert.Equal(t, EXPECTED_API_HOST_LOCAL_CONF, config.Host)
+	assert.Equal(t, "https://"+EXPECTED_API_HOST_LOCAL_CONF+"/api", config.BaseURL.String())
+	assert.Equal(t, EXPECTED_TEST_AUTH_KEY, config.AuthToken)
+	assert.Equal(t, EXPECTED_NAMESPACE_WHISK, config.Namespace)
+	assert.Equal(t, EXPECTED_AUTH_API_KEY, config.ApigwAccessToken)
+	assert.Equal(t, EXPECTED_APIGW_TENANT_ID, config.ApigwTenantId)
+	assert.Equal(t, EXPECTED_CERT_LOCAL_CONF, config.Cert)
+	assert.Equal(t, EXPECTED_KEY, config.Key)
+	assert.False(t, config.Insecure)
+
+	// Without the INSECURE property, the server certificates are not verified, as with GetDefaultConfig
+	resolver.Profile = ""
+	resolved, err = resolver.Resolve()
+	assert.Nil(t, err)
+	assert.Equal(t, DEFAULT_INSECURE, resolved.Props.Insecure)
+	assert.Equal(t, CONFIG_SOURCE_DEFAULT, resolved.Sources[INSECURE])
+	assert.True(t, resolved.Config().Insecure)
+
+	// An INSECURE value that is not a boolean is an error, rather than skipping the verification
+	resolver.OsPackage.(FakeOSPackage).StoredValues[WSK_ENV_PREFIX+INSECURE] = "no"
+	_, err = resolver.Resolve()
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "Invalid INSECURE value 'no' from "+CONFIG_SOURCE_ENV)
+	}
+	delete(resolver.OsPackage.(FakeOSPackage).StoredValues, WSK_ENV_PREFIX+INSECURE)
+	assert.False(t, convertWskpropsToConfig(&Wskprops{Insecure: "off"}).Insecure)
+	assert.False(t, convertWskpropsToConfig(&Wskprops{Insecure: "false"}).Insecure)
+	assert.True(t, convertWskpropsToConfig(&Wskprops{}).Insecure)
+
+	resolver.Profile = "staging"
+	_, err = resolver.Resolve()
+	assert.NotNil(t, err)
+}
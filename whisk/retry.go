@@ -0,0 +1,238 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package whisk
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apache/openwhisk-client-go/wski18n"
+)
+
+const (
+	DEFAULT_RETRY_INITIAL_BACKOFF = 500 * time.Millisecond
+	DEFAULT_RETRY_MAX_BACKOFF     = 30 * time.Second
+)
+
+// RetryPolicy controls how Client.Do retries requests that fail with a transient error: a network
+// failure, an HTTP 429 (throttled), or an HTTP 502/503/504 from the controller or a gateway in front of it.
+//
+// Only idempotent requests (GET, HEAD, PUT, DELETE, OPTIONS) are retried by default.  The action invocations
+// and trigger fires (ActionService.Invoke, TriggerService.Fire) may start a second activation when retried, so
+// they are only retried when RetryNonIdempotent is set.  The other POST requests, such as creating an API or
+// enabling a rule, are never retried.
+//
+// A Retry-After delay sent by the server is honored, but never longer than MaxBackoff.
+type RetryPolicy struct {
+	MaxAttempts        int           // Total number of attempts, including the first one; less than 2 disables retries
+	InitialBackoff     time.Duration // Delay before the first retry; doubled for each further retry.  Default is 500ms
+	MaxBackoff         time.Duration // Upper bound of the delay between two attempts, Retry-After included.  Default is 30s
+	RetryNonIdempotent bool          // When true, the action invocations and trigger fires are retried as well
+}
+
+var idempotentMethods = map[string]bool{
+	"GET":     true,
+	"HEAD":    true,
+	"PUT":     true,
+	"DELETE":  true,
+	"OPTIONS": true,
+}
+
+// sendRequest issues the request through the HTTP client, retrying it according to the client's RetryPolicy,
+// and returns the number of retries.  The request body is rewound before each new attempt.
+func (c *Client) sendRequest(req *http.Request) (*http.Response, int, error) {
+	policy := c.Config.Retry
+	if !policy.canRetry(req) {
+		resp, err := c.sendAuthenticated(req)
+		return resp, 0, err
+	}
+
+	for attempt := 1; ; attempt++ {
+		resp, err := c.sendAuthenticated(req)
+		if attempt >= policy.MaxAttempts || !policy.shouldRetry(req, resp, err) {
+			return resp, attempt - 1, err
+		}
+
+		delay := policy.backoff(attempt, resp)
+		fields := LogFields{"method": req.Method, "url": c.redactedURL(req), "attempt": attempt, "delay": delay}
+		if err != nil {
+			c.debug(DbgWarn, "HTTP %s %s attempt %d failed: %s; retrying in %v\n", req.Method, c.redactedURL(req), attempt, err, delay)
+			fields["error"] = err
+		} else {
+			c.debug(DbgWarn, "HTTP %s %s attempt %d returned status %d; retrying in %v\n", req.Method, c.redactedURL(req), attempt, resp.StatusCode, delay)
+			fields["status"] = resp.StatusCode
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		c.log(LogWarn, "Retrying HTTP request", fields)
+
+		if err = sleepContext(req.Context(), delay); err != nil {
+			return nil, attempt, err
+		}
+		if req, err = c.rewindRequest(req); err != nil {
+			return nil, attempt, err
+		}
+	}
+}
+
+func (policy *RetryPolicy) canRetry(req *http.Request) bool {
+	if policy == nil || policy.MaxAttempts < 2 {
+		return false
+	}
+	if !idempotentMethods[req.Method] && !(policy.RetryNonIdempotent && activationCollection(req) != "") {
+		return false
+	}
+	// A body that cannot be recreated can only be sent once
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+func (policy *RetryPolicy) shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if err != nil {
+		// Do not retry when the caller gave up on the request
+		return req.Context().Err() == nil
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	case http.StatusBadGateway:
+		// A blocking invocation reports an action's application error with a 502, so only
+		// treat it as a gateway failure for requests that do not start an activation
+		return idempotentMethods[req.Method]
+	}
+
+	return false
+}
+
+// backoff returns the delay before the next attempt: an exponential backoff with jitter, or the
+// server's Retry-After value when one is present, both bounded by MaxBackoff.
+func (policy *RetryPolicy) backoff(attempt int, resp *http.Response) time.Duration {
+	max := policy.MaxBackoff
+	if max <= 0 {
+		max = DEFAULT_RETRY_MAX_BACKOFF
+	}
+
+	if resp != nil {
+		if delay, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			if delay > max {
+				delay = max
+			}
+			return delay
+		}
+	}
+
+	initial := policy.InitialBackoff
+	if initial <= 0 {
+		initial = DEFAULT_RETRY_INITIAL_BACKOFF
+	}
+
+	delay := initial
+	for i := 1; i < attempt && delay < max; i++ {
+		delay *= 2
+	}
+	if delay > max {
+		delay = max
+	}
+
+	// Equal jitter: keep half of the delay, randomize the other half
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// activationCollection returns the collection of a request starting an activation, "actions" for an invocation
+// (a POST to .../namespaces/{namespace}/actions/{name}) and "triggers" for a fire (a POST to
+// .../namespaces/{namespace}/triggers/{name}), or "" for any other request.
+func activationCollection(req *http.Request) string {
+	if req.Method != "POST" {
+		return ""
+	}
+	segments := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	for i, segment := range segments {
+		if segment != "namespaces" {
+			continue
+		}
+		// namespaces/{namespace}/{collection}/{name}, where an action name may be qualified by its package
+		names := len(segments) - i - 3
+		if names < 1 {
+			return ""
+		}
+		switch collection := segments[i+2]; {
+		case collection == "actions" && names <= 2:
+			return collection
+		case collection == "triggers" && names == 1:
+			return collection
+		}
+		return ""
+	}
+	return ""
+}
+
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if len(value) == 0 {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		delay := time.Until(date)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+	return 0, false
+}
+
+// rewindRequest returns a copy of req with a fresh body, so that the request can be sent again.
+func (c *Client) rewindRequest(req *http.Request) (*http.Request, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return req, nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		c.debug(DbgError, "req.GetBody() error: %s\n", err)
+		errStr := wski18n.T("Unable to rewind the request body for a retry: {{.err}}",
+			map[string]interface{}{"err": err})
+		return nil, MakeWskError(errors.New(errStr), EXIT_CODE_ERR_GENERAL, DISPLAY_MSG, NO_DISPLAY_USAGE)
+	}
+
+	retry := req.Clone(req.Context())
+	retry.Body = body
+	return retry, nil
+}
+
+// sleepContext waits for the given duration, returning early with the context's error when it is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
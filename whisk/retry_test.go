@@ -0,0 +1,149 @@
+// +build unit
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package whisk
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newFlakyServer returns a server that answers the first `failures` requests with `status`, then succeeds.
+// Every request body received is recorded in bodies.
+func newFlakyServer(failures int, status int, bodies *[]string) *httptest.Server {
+	count := 0
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		*bodies = append(*bodies, string(body))
+		count++
+		if count <= failures {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(status)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"name":"test","namespace":"my_namespace"}`))
+	}))
+}
+
+func newRetryTestClient(t *testing.T, server *httptest.Server, policy *RetryPolicy) *Client {
+	config := GetValidConfigTest()
+	config.Retry = policy
+	return newLocalTestClient(t, server, config)
+}
+
+func TestRetryIdempotentRequest(t *testing.T) {
+	var bodies []string
+	server := newFlakyServer(2, http.StatusServiceUnavailable, &bodies)
+	defer server.Close()
+
+	client := newRetryTestClient(t, server, &RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond})
+	action, _, err := client.Actions.Insert(&Action{Name: "test"}, true)
+	assert.Nil(t, err)
+	assert.Equal(t, "test", action.Name)
+	assert.Equal(t, 3, len(bodies))
+	for _, body := range bodies {
+		assert.Contains(t, body, `"name":"test"`, "request body was not rewound for the retry")
+	}
+}
+
+func TestRetryGivesUpAfterMaxAttempts(t *testing.T) {
+	var bodies []string
+	server := newFlakyServer(5, http.StatusTooManyRequests, &bodies)
+	defer server.Close()
+
+	client := newRetryTestClient(t, server, &RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond})
+	_, resp, err := client.Actions.Get("test", false)
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, 2, len(bodies))
+}
+
+func TestRetryNonIdempotentIsOptIn(t *testing.T) {
+	var bodies []string
+	server := newFlakyServer(1, http.StatusServiceUnavailable, &bodies)
+	defer server.Close()
+
+	client := newRetryTestClient(t, server, &RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond})
+	_, _, err := client.Actions.Invoke("test", map[string]interface{}{"key": "value"}, false, false)
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, len(bodies))
+
+	bodies = nil
+	server2 := newFlakyServer(1, http.StatusServiceUnavailable, &bodies)
+	defer server2.Close()
+
+	client = newRetryTestClient(t, server2, &RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, RetryNonIdempotent: true})
+	_, _, err = client.Actions.Invoke("test", map[string]interface{}{"key": "value"}, false, false)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(bodies))
+	assert.Equal(t, bodies[0], bodies[1])
+	// The other POST requests are never retried
+	bodies = nil
+	server3 := newFlakyServer(1, http.StatusServiceUnavailable, &bodies)
+	defer server3.Close()
+
+	client = newRetryTestClient(t, server3, &RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, RetryNonIdempotent: true})
+	_, _, err = client.Rules.SetState("test", "active")
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, len(bodies))
+}
+
+func TestActivationCollection(t *testing.T) {
+	collections := map[string]string{
+		"POST /api/v1/namespaces/_/actions/hello":              "actions",
+		"POST /api/v1/namespaces/_/actions/pkg/hello":          "actions",
+		"POST /api/v1/namespaces/guest/triggers/fire":          "triggers",
+		"PUT /api/v1/namespaces/_/actions/hello":               "",
+		"GET /api/v1/namespaces/_/triggers/fire":               "",
+		"POST /api/v1/namespaces/_/rules/rule":                 "",
+		"POST /api/v1/namespaces/_/actions":                    "",
+		"POST /api/v1/web/guest/apimgmt/createApi.http":        "",
+		"POST /api/v1/namespaces/_/packages/pkg":               "",
+		"POST /gateway/api/v1/namespaces/_/triggers/fire/more": "",
+	}
+	for request, collection := range collections {
+		method, path := request[:strings.Index(request, " ")], request[strings.Index(request, " ")+1:]
+		req, err := http.NewRequest(method, "https://example.com"+path, nil)
+		assert.Nil(t, err)
+		assert.Equal(t, collection, activationCollection(req), request)
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	policy := &RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: 300 * time.Millisecond}
+
+	delay := policy.backoff(1, nil)
+	assert.True(t, delay >= 50*time.Millisecond && delay <= 100*time.Millisecond, "unexpected delay %v", delay)
+	delay = policy.backoff(5, nil)
+	assert.True(t, delay >= 150*time.Millisecond && delay <= 300*time.Millisecond, "unexpected delay %v", delay)
+
+	resp := &http.Response{Header: http.Header{}}
+	resp.Header.Set("Retry-After", "2")
+	assert.Equal(t, 2*time.Second, (&RetryPolicy{}).backoff(1, resp))
+
+	// The Retry-After delay is bounded by MaxBackoff
+	resp.Header.Set("Retry-After", "3600")
+	assert.Equal(t, 300*time.Millisecond, policy.backoff(1, resp))
+}
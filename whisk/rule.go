@@ -18,6 +18,7 @@
 package whisk
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/apache/openwhisk-client-go/wski18n"
@@ -80,10 +81,14 @@ func (rule Rule) ToSummaryRowString() string {
 }
 
 func (s *RuleService) List(options *RuleListOptions) ([]Rule, *http.Response, error) {
+	return s.ListContext(context.Background(), options)
+}
+
+func (s *RuleService) ListContext(ctx context.Context, options *RuleListOptions) ([]Rule, *http.Response, error) {
 	route := "rules"
-	routeUrl, err := addRouteOptions(route, options)
+	routeUrl, err := addRouteOptions(s.client, route, options)
 	if err != nil {
-		Debug(DbgError, "addRouteOptions(%s, %#v) error: '%s'\n", route, options, err)
+		s.client.debug(DbgError, "addRouteOptions(%s, %#v) error: '%s'\n", route, options, err)
 		errStr := wski18n.T("Unable to append options '{{.options}}' to URL route '{{.route}}': {{.err}}",
 			map[string]interface{}{"options": fmt.Sprintf("%#v", options), "route": route, "err": err})
 		werr := MakeWskError(errors.New(errStr), EXIT_CODE_ERR_GENERAL, DISPLAY_MSG, NO_DISPLAY_USAGE)
@@ -92,7 +97,7 @@ func (s *RuleService) List(options *RuleListOptions) ([]Rule, *http.Response, er
 
 	req, err := s.client.NewRequestUrl("GET", routeUrl, nil, IncludeNamespaceInUrl, AppendOpenWhiskPathPrefix, EncodeBodyAsJson, AuthRequired)
 	if err != nil {
-		Debug(DbgError, "http.NewRequestUrl(GET, %s, nil, IncludeNamespaceInUrl, AppendOpenWhiskPathPrefix, EncodeBodyAsJson, AuthRequired); error: '%s'\n", route, err)
+		s.client.debug(DbgError, "http.NewRequestUrl(GET, %s, nil, IncludeNamespaceInUrl, AppendOpenWhiskPathPrefix, EncodeBodyAsJson, AuthRequired); error: '%s'\n", route, err)
 		errStr := wski18n.T("Unable to create HTTP request for GET '{{.route}}': {{.err}}",
 			map[string]interface{}{"route": route, "err": err})
 		werr := MakeWskErrorFromWskError(errors.New(errStr), err, EXIT_CODE_ERR_GENERAL, DISPLAY_MSG, NO_DISPLAY_USAGE)
@@ -100,9 +105,9 @@ func (s *RuleService) List(options *RuleListOptions) ([]Rule, *http.Response, er
 	}
 
 	var rules []Rule
-	resp, err := s.client.Do(req, &rules, ExitWithSuccessOnTimeout)
+	resp, err := s.client.DoContext(ctx, req, &rules, ExitWithSuccessOnTimeout)
 	if err != nil {
-		Debug(DbgError, "s.client.Do() error - HTTP req %s; error: '%s'\n", req.URL.String(), err)
+		s.client.debug(DbgError, "s.client.Do() error - HTTP req %s; error: '%s'\n", s.client.redactedURL(req), err)
 		return nil, resp, err
 	}
 
@@ -110,6 +115,10 @@ func (s *RuleService) List(options *RuleListOptions) ([]Rule, *http.Response, er
 }
 
 func (s *RuleService) Insert(rule *Rule, overwrite bool) (*Rule, *http.Response, error) {
+	return s.InsertContext(context.Background(), rule, overwrite)
+}
+
+func (s *RuleService) InsertContext(ctx context.Context, rule *Rule, overwrite bool) (*Rule, *http.Response, error) {
 	// Encode resource name as a path (with no query params) before inserting it into the URI
 	// This way any '?' chars in the name won't be treated as the beginning of the query params
 	ruleName := (&url.URL{Path: rule.Name}).String()
@@ -117,7 +126,7 @@ func (s *RuleService) Insert(rule *Rule, overwrite bool) (*Rule, *http.Response,
 
 	routeUrl, err := url.Parse(route)
 	if err != nil {
-		Debug(DbgError, "url.Parse(%s) error: %s\n", route, err)
+		s.client.debug(DbgError, "url.Parse(%s) error: %s\n", route, err)
 		errStr := wski18n.T("Invalid request URL '{{.url}}': {{.err}}",
 			map[string]interface{}{"url": route, "err": err})
 		werr := MakeWskError(errors.New(errStr), EXIT_CODE_ERR_GENERAL, DISPLAY_MSG, NO_DISPLAY_USAGE)
@@ -126,7 +135,7 @@ func (s *RuleService) Insert(rule *Rule, overwrite bool) (*Rule, *http.Response,
 
 	req, err := s.client.NewRequestUrl("PUT", routeUrl, rule, IncludeNamespaceInUrl, AppendOpenWhiskPathPrefix, EncodeBodyAsJson, AuthRequired)
 	if err != nil {
-		Debug(DbgError, "http.NewRequestUrl(PUT, %s, %+v, IncludeNamespaceInUrl, AppendOpenWhiskPathPrefix, EncodeBodyAsJson, AuthRequired); error: '%s'\n", routeUrl, rule, err)
+		s.client.debug(DbgError, "http.NewRequestUrl(PUT, %s, %+v, IncludeNamespaceInUrl, AppendOpenWhiskPathPrefix, EncodeBodyAsJson, AuthRequired); error: '%s'\n", routeUrl, rule, err)
 		errStr := wski18n.T("Unable to create HTTP request for PUT '{{.route}}': {{.err}}",
 			map[string]interface{}{"route": routeUrl, "err": err})
 		werr := MakeWskErrorFromWskError(errors.New(errStr), err, EXIT_CODE_ERR_GENERAL, DISPLAY_MSG, NO_DISPLAY_USAGE)
@@ -134,9 +143,9 @@ func (s *RuleService) Insert(rule *Rule, overwrite bool) (*Rule, *http.Response,
 	}
 
 	r := new(Rule)
-	resp, err := s.client.Do(req, &r, ExitWithSuccessOnTimeout)
+	resp, err := s.client.DoContext(ctx, req, &r, ExitWithSuccessOnTimeout)
 	if err != nil {
-		Debug(DbgError, "s.client.Do() error - HTTP req %s; error: '%s'\n", req.URL.String(), err)
+		s.client.debug(DbgError, "s.client.Do() error - HTTP req %s; error: '%s'\n", s.client.redactedURL(req), err)
 		return nil, resp, err
 	}
 
@@ -144,6 +153,10 @@ func (s *RuleService) Insert(rule *Rule, overwrite bool) (*Rule, *http.Response,
 }
 
 func (s *RuleService) Get(ruleName string) (*Rule, *http.Response, error) {
+	return s.GetContext(context.Background(), ruleName)
+}
+
+func (s *RuleService) GetContext(ctx context.Context, ruleName string) (*Rule, *http.Response, error) {
 	// Encode resource name as a path (with no query params) before inserting it into the URI
 	// This way any '?' chars in the name won't be treated as the beginning of the query params
 	ruleName = (&url.URL{Path: ruleName}).String()
@@ -151,7 +164,7 @@ func (s *RuleService) Get(ruleName string) (*Rule, *http.Response, error) {
 
 	req, err := s.client.NewRequest("GET", route, nil, IncludeNamespaceInUrl)
 	if err != nil {
-		Debug(DbgError, "http.NewRequest(GET, %s); error: '%s'\n", route, err)
+		s.client.debug(DbgError, "http.NewRequest(GET, %s); error: '%s'\n", route, err)
 		errStr := wski18n.T("Unable to create HTTP request for GET '{{.route}}': {{.err}}",
 			map[string]interface{}{"route": route, "err": err})
 		werr := MakeWskErrorFromWskError(errors.New(errStr), err, EXIT_CODE_ERR_GENERAL, DISPLAY_MSG, NO_DISPLAY_USAGE)
@@ -159,9 +172,9 @@ func (s *RuleService) Get(ruleName string) (*Rule, *http.Response, error) {
 	}
 
 	r := new(Rule)
-	resp, err := s.client.Do(req, &r, ExitWithSuccessOnTimeout)
+	resp, err := s.client.DoContext(ctx, req, &r, ExitWithSuccessOnTimeout)
 	if err != nil {
-		Debug(DbgError, "s.client.Do() error - HTTP req %s; error: '%s'\n", req.URL.String(), err)
+		s.client.debug(DbgError, "s.client.Do() error - HTTP req %s; error: '%s'\n", s.client.redactedURL(req), err)
 		return nil, resp, err
 	}
 
@@ -169,6 +182,10 @@ func (s *RuleService) Get(ruleName string) (*Rule, *http.Response, error) {
 }
 
 func (s *RuleService) Delete(ruleName string) (*http.Response, error) {
+	return s.DeleteContext(context.Background(), ruleName)
+}
+
+func (s *RuleService) DeleteContext(ctx context.Context, ruleName string) (*http.Response, error) {
 	// Encode resource name as a path (with no query params) before inserting it into the URI
 	// This way any '?' chars in the name won't be treated as the beginning of the query params
 	ruleName = (&url.URL{Path: ruleName}).String()
@@ -176,16 +193,16 @@ func (s *RuleService) Delete(ruleName string) (*http.Response, error) {
 
 	req, err := s.client.NewRequest("DELETE", route, nil, IncludeNamespaceInUrl)
 	if err != nil {
-		Debug(DbgError, "http.NewRequest(DELETE, %s); error: '%s'\n", route, err)
+		s.client.debug(DbgError, "http.NewRequest(DELETE, %s); error: '%s'\n", route, err)
 		errStr := wski18n.T("Unable to create HTTP request for DELETE '{{.route}}': {{.err}}",
 			map[string]interface{}{"route": route, "err": err})
 		werr := MakeWskErrorFromWskError(errors.New(errStr), err, EXIT_CODE_ERR_GENERAL, DISPLAY_MSG, NO_DISPLAY_USAGE)
 		return nil, werr
 	}
 
-	resp, err := s.client.Do(req, nil, ExitWithSuccessOnTimeout)
+	resp, err := s.client.DoContext(ctx, req, nil, ExitWithSuccessOnTimeout)
 	if err != nil {
-		Debug(DbgError, "s.client.Do() error - HTTP req %s; error: '%s'\n", req.URL.String(), err)
+		s.client.debug(DbgError, "s.client.Do() error - HTTP req %s; error: '%s'\n", s.client.redactedURL(req), err)
 		return resp, err
 	}
 
@@ -193,6 +210,10 @@ func (s *RuleService) Delete(ruleName string) (*http.Response, error) {
 }
 
 func (s *RuleService) SetState(ruleName string, state string) (*Rule, *http.Response, error) {
+	return s.SetStateContext(context.Background(), ruleName, state)
+}
+
+func (s *RuleService) SetStateContext(ctx context.Context, ruleName string, state string) (*Rule, *http.Response, error) {
 	state = strings.ToLower(state)
 	if state != "active" && state != "inactive" {
 		errStr := wski18n.T("Internal error. Invalid state option '{{.state}}'. Valid options are \"active\" and \"inactive\".",
@@ -210,7 +231,7 @@ func (s *RuleService) SetState(ruleName string, state string) (*Rule, *http.Resp
 
 	req, err := s.client.NewRequest("POST", route, ruleState, IncludeNamespaceInUrl)
 	if err != nil {
-		Debug(DbgError, "http.NewRequest(POST, %s); error: '%s'\n", route, err)
+		s.client.debug(DbgError, "http.NewRequest(POST, %s); error: '%s'\n", route, err)
 		errStr := wski18n.T("Unable to create HTTP request for POST '{{.route}}': {{.err}}",
 			map[string]interface{}{"route": route, "err": err})
 		werr := MakeWskErrorFromWskError(errors.New(errStr), err, EXIT_CODE_ERR_GENERAL, DISPLAY_MSG, NO_DISPLAY_USAGE)
@@ -218,9 +239,9 @@ func (s *RuleService) SetState(ruleName string, state string) (*Rule, *http.Resp
 	}
 
 	r := new(Rule)
-	resp, err := s.client.Do(req, &r, ExitWithSuccessOnTimeout)
+	resp, err := s.client.DoContext(ctx, req, &r, ExitWithSuccessOnTimeout)
 	if err != nil {
-		Debug(DbgError, "s.client.Do() error - HTTP req %s; error: '%s'\n", req.URL.String(), err)
+		s.client.debug(DbgError, "s.client.Do() error - HTTP req %s; error: '%s'\n", s.client.redactedURL(req), err)
 		return nil, resp, err
 	}
 
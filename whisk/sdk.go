@@ -18,10 +18,12 @@
 package whisk
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/apache/openwhisk-client-go/wski18n"
 	"net/http"
+	"time"
 )
 
 type SdkService struct {
@@ -39,27 +41,33 @@ type SdkRequest struct {
 
 // Install artifact {component = docker || swift || iOS}
 func (s *SdkService) Install(relFileUrl string) (*http.Response, error) {
-	baseURL := s.client.Config.BaseURL
+	return s.InstallContext(context.Background(), relFileUrl)
+}
+
+func (s *SdkService) InstallContext(ctx context.Context, relFileUrl string) (*http.Response, error) {
+	baseURL := *s.client.Config.BaseURL
 	// Remove everything but the scheme, host, and port
 	baseURL.Path, baseURL.RawQuery, baseURL.Fragment = "", "", ""
 
-	urlStr := fmt.Sprintf("%s/%s", baseURL, relFileUrl)
+	urlStr := fmt.Sprintf("%s/%s", baseURL.String(), relFileUrl)
 
-	req, err := http.NewRequest("GET", urlStr, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", urlStr, nil)
 	if err != nil {
-		Debug(DbgError, "http.NewRequest(GET, %s, nil) error: %s\n", urlStr, err)
+		s.client.debug(DbgError, "http.NewRequestWithContext(GET, %s, nil) error: %s\n", urlStr, err)
 		errStr := wski18n.T("Unable to create HTTP request for GET '{{.url}}': {{.err}}",
 			map[string]interface{}{"url": urlStr, "err": err})
 		werr := MakeWskError(errors.New(errStr), EXIT_CODE_ERR_GENERAL, DISPLAY_MSG, NO_DISPLAY_USAGE)
 		return nil, werr
 	}
 
-	if IsVerbose() {
+	if s.client.Config.Logger != nil {
+		s.client.logRequest(req, nil)
+	} else if s.client.isVerbose() {
 		fmt.Println("REQUEST:")
-		fmt.Printf("[%s]\t%s\n", req.Method, req.URL)
+		fmt.Printf("[%s]\t%s\n", req.Method, s.client.redactedURL(req))
 		if len(req.Header) > 0 {
 			fmt.Println("Req Headers")
-			PrintJSON(req.Header)
+			PrintJSON(s.client.redactor().header(req.Header))
 		}
 		if req.Body != nil {
 			fmt.Println("Req Body")
@@ -68,9 +76,38 @@ func (s *SdkService) Install(relFileUrl string) (*http.Response, error) {
 	}
 
 	// Directly use the HTTP client, not the Whisk CLI client, so that the response body is left alone
-	resp, err := s.client.client.Do(req)
+	send := func(ctx context.Context, req *http.Request, v interface{}) (*http.Response, error) {
+		start := time.Now()
+		req = withCorrelationId(req.WithContext(ctx))
+		var timer *harTimer
+		if s.client.Config.HAR != nil {
+			timer = newHARTimer()
+			req = timer.withTrace(req)
+		}
+		resp, err := s.client.client.Do(req)
+		if err != nil {
+			s.client.log(LogError, "HTTP request failed", LogFields{"method": req.Method, "url": s.client.redactedURL(req),
+				"duration": time.Since(start), "error": err})
+			if timer != nil {
+				s.client.recordHAR(timer, req, nil, nil, nil, 0, nil, err)
+			}
+			return resp, err
+		}
+		s.client.log(LogInfo, "HTTP response", LogFields{"method": req.Method, "url": s.client.redactedURL(req),
+			"status": resp.StatusCode, "duration": time.Since(start)})
+		if timer != nil {
+			// The downloaded SDK is recorded once the caller is done reading it, without its content
+			timer.responded()
+			resp.Body = &harBody{ReadCloser: resp.Body, record: func(size int) {
+				s.client.recordHAR(timer, req, nil, resp, nil, size, nil, nil)
+			}}
+		}
+		return resp, nil
+	}
+
+	resp, err := s.client.chain(send)(ctx, req, nil)
 	if err != nil {
-		Debug(DbgError, "s.client.Do() error - HTTP req %s; error: '%s'\n", req.URL.String(), err)
+		s.client.debug(DbgError, "s.client.Do() error - HTTP req %s; error: '%s'\n", s.client.redactedURL(req), err)
 		return resp, err
 	}
 
@@ -0,0 +1,250 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package whisk
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"github.com/apache/openwhisk-client-go/wski18n"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// newTLSConfig returns the TLS configuration of the client, or nil when the default configuration applies.
+func (c *Client) newTLSConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: c.Config.Insecure,
+		MinVersion:         c.Config.MinTLSVersion,
+	}
+
+	hasCert := c.Config.Cert != "" || len(c.Config.CertPEM) > 0
+	hasKey := c.Config.Key != "" || len(c.Config.KeyPEM) > 0
+	if hasCert && hasKey {
+		var err error
+		if len(c.Config.CertPEM) > 0 && len(c.Config.KeyPEM) > 0 {
+			var cert tls.Certificate
+			if cert, err = tls.X509KeyPair(c.Config.CertPEM, c.Config.KeyPEM); err == nil {
+				tlsConfig.Certificates = []tls.Certificate{cert}
+			}
+		} else if c.Config.Cert != "" && c.Config.Key != "" {
+			var reloader *certReloader
+			if reloader, err = newCertReloader(c, c.Config.Cert, c.Config.Key); err == nil {
+				tlsConfig.GetClientCertificate = reloader.GetClientCertificate
+			}
+		} else {
+			err = errors.New(wski18n.T("the certificate and the key must both be files, or both be PEM data"))
+		}
+		if err != nil {
+			errStr := wski18n.T("Unable to load the X509 key pair due to the following reason: {{.err}}",
+				map[string]interface{}{"err": err})
+			werr := MakeWskError(errors.New(errStr), EXIT_CODE_ERR_GENERAL, DISPLAY_MSG, NO_DISPLAY_USAGE)
+			return nil, werr
+		}
+	} else if !c.Config.Insecure {
+		if !hasCert {
+			warningStr := "The Cert file is not configured. Please configure the missing Cert file, if there is a security issue accessing the service.\n"
+			c.debug(DbgWarn, warningStr)
+			if hasKey {
+				errStr := wski18n.T("The Cert file is not configured. Please configure the missing Cert file.\n")
+				werr := MakeWskError(errors.New(errStr), EXIT_CODE_ERR_GENERAL, DISPLAY_MSG, NO_DISPLAY_USAGE)
+				return nil, werr
+			}
+		}
+		if !hasKey {
+			warningStr := "The Key file is not configured. Please configure the missing Key file, if there is a security issue accessing the service.\n"
+			c.debug(DbgWarn, warningStr)
+			if hasCert {
+				errStr := wski18n.T("The Key file is not configured. Please configure the missing Key file.\n")
+				werr := MakeWskError(errors.New(errStr), EXIT_CODE_ERR_GENERAL, DISPLAY_MSG, NO_DISPLAY_USAGE)
+				return nil, werr
+			}
+		}
+	}
+
+	if c.Config.CACert != "" || len(c.Config.CACertPEM) > 0 {
+		pool, err := loadCertPool(c.Config.CACert, c.Config.CACertPEM)
+		if err != nil {
+			errStr := wski18n.T("Unable to load the CA certificates due to the following reason: {{.err}}",
+				map[string]interface{}{"err": err})
+			werr := MakeWskError(errors.New(errStr), EXIT_CODE_ERR_GENERAL, DISPLAY_MSG, NO_DISPLAY_USAGE)
+			return nil, werr
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if !tlsConfig.InsecureSkipVerify && tlsConfig.Certificates == nil && tlsConfig.GetClientCertificate == nil &&
+		tlsConfig.RootCAs == nil && tlsConfig.MinVersion == 0 {
+		return nil, nil
+	}
+	return tlsConfig, nil
+}
+
+// loadCertPool returns a pool of the CA certificates of a PEM file and of PEM data.
+func loadCertPool(file string, data []byte) (*x509.CertPool, error) {
+	pool := x509.NewCertPool()
+	if file != "" {
+		fileData, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if !pool.AppendCertsFromPEM(fileData) {
+			return nil, errors.New(wski18n.T("no certificate found in '{{.file}}'", map[string]interface{}{"file": file}))
+		}
+	}
+	if len(data) > 0 && !pool.AppendCertsFromPEM(data) {
+		return nil, errors.New(wski18n.T("no certificate found in the CA certificate data"))
+	}
+	return pool, nil
+}
+
+// certReloader provides the client certificate of a certificate file and a key file, and loads them again when
+// they are modified.  A reloaded certificate is used for the new connections.
+type certReloader struct {
+	client   *Client // Client whose Logger traces the reloads
+	certFile string
+	keyFile  string
+
+	mu      sync.Mutex
+	cert    *tls.Certificate
+	certMod time.Time
+	keyMod  time.Time
+}
+
+func newCertReloader(client *Client, certFile string, keyFile string) (*certReloader, error) {
+	reloader := &certReloader{client: client, certFile: certFile, keyFile: keyFile}
+	if err := reloader.reload(); err != nil {
+		return nil, err
+	}
+	return reloader, nil
+}
+
+func (r *certReloader) GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.modified() {
+		// Keep the current certificate when the files cannot be loaded, e.g. while they are being replaced
+		if err := r.reload(); err != nil {
+			r.client.debug(DbgWarn, "Unable to reload the X509 key pair '%s' and '%s': %s\n", r.certFile, r.keyFile, err)
+		}
+	}
+	return r.cert, nil
+}
+
+func (r *certReloader) modified() bool {
+	certInfo, certErr := os.Stat(r.certFile)
+	keyInfo, keyErr := os.Stat(r.keyFile)
+	return certErr == nil && keyErr == nil && (!certInfo.ModTime().Equal(r.certMod) || !keyInfo.ModTime().Equal(r.keyMod))
+}
+
+func (r *certReloader) reload() error {
+	var certMod, keyMod time.Time
+	if info, err := os.Stat(r.certFile); err == nil {
+		certMod = info.ModTime()
+	}
+	if info, err := os.Stat(r.keyFile); err == nil {
+		keyMod = info.ModTime()
+	}
+
+	cert, err := ReadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return err
+	}
+	r.client.debug(DbgInfo, "Loaded the X509 key pair '%s' and '%s'\n", r.certFile, r.keyFile)
+	r.cert, r.certMod, r.keyMod = &cert, certMod, keyMod
+	return nil
+}
+
+// RoundTripperWrapper is implemented by the custom http.RoundTrippers wrapping a base transport, e.g. for
+// instrumentation, so that NewClient can apply the TLS options of the configuration to their base transport.
+type RoundTripperWrapper interface {
+	http.RoundTripper
+	BaseTransport() http.RoundTripper                                // Wrapped RoundTripper; nil for http.DefaultTransport
+	WithBaseTransport(transport http.RoundTripper) http.RoundTripper // Copy of the wrapper, wrapping transport instead
+}
+
+// wrapTLSConfig returns a copy of a RoundTripper with the TLS configuration applied to its transport, and the
+// copied transport.  It returns false when the RoundTripper is neither an http.Transport nor a RoundTripperWrapper.
+func wrapTLSConfig(roundTripper http.RoundTripper, tlsConfig *tls.Config) (http.RoundTripper, *http.Transport, bool) {
+	switch rt := roundTripper.(type) {
+	case nil:
+		transport := newDefaultTransport(tlsConfig)
+		return transport, transport, true
+	case *http.Transport:
+		// Keep the settings of the provided transport, and leave the provided transport itself untouched
+		transport := applyTLSConfig(rt, tlsConfig)
+		return transport, transport, true
+	case RoundTripperWrapper:
+		base, transport, ok := wrapTLSConfig(rt.BaseTransport(), tlsConfig)
+		if !ok {
+			return roundTripper, nil, false
+		}
+		return rt.WithBaseTransport(base), transport, true
+	}
+	return roundTripper, nil, false
+}
+
+// newDefaultTransport returns a transport with the settings of http.DefaultTransport, to maintain proxy support,
+// and the TLS configuration.
+func newDefaultTransport(tlsConfig *tls.Config) *http.Transport {
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+			DualStack: true,
+		}).DialContext,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+		TLSClientConfig:       tlsConfig,
+	}
+}
+
+// applyTLSConfig returns a copy of the transport with the TLS configuration applied.  The settings of the
+// transport's own TLS configuration are kept, unless the client's configuration overrides them.
+func applyTLSConfig(transport *http.Transport, tlsConfig *tls.Config) *http.Transport {
+	transport = transport.Clone()
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = tlsConfig
+		return transport
+	}
+
+	merged := transport.TLSClientConfig.Clone()
+	if tlsConfig.InsecureSkipVerify {
+		merged.InsecureSkipVerify = true
+	}
+	if tlsConfig.Certificates != nil || tlsConfig.GetClientCertificate != nil {
+		merged.Certificates = tlsConfig.Certificates
+		merged.GetClientCertificate = tlsConfig.GetClientCertificate
+	}
+	if tlsConfig.RootCAs != nil {
+		merged.RootCAs = tlsConfig.RootCAs
+	}
+	if tlsConfig.MinVersion != 0 {
+		merged.MinVersion = tlsConfig.MinVersion
+	}
+	transport.TLSClientConfig = merged
+	return transport
+}
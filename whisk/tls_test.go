@@ -0,0 +1,255 @@
+// +build unit
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package whisk
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// newTestClientCert returns a self-signed client certificate and its key, as PEM data.
+func newTestClientCert(t *testing.T, name string) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.Nil(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	assert.Nil(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+}
+
+// newMutualTLSServer returns a server requiring one of the given client certificates, which answers with the
+// common name of the client certificate.
+func newMutualTLSServer(clientCerts ...[]byte) *httptest.Server {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode([]string{r.TLS.PeerCertificates[0].Subject.CommonName})
+	}))
+	pool := x509.NewCertPool()
+	for _, cert := range clientCerts {
+		pool.AppendCertsFromPEM(cert)
+	}
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: pool, MaxVersion: tls.VersionTLS12}
+	server.StartTLS()
+	return server
+}
+
+func serverCAPEM(server *httptest.Server) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+}
+
+func getClientCertName(client *Client) (string, error) {
+	req, err := client.NewRequest("GET", "namespaces", nil, false)
+	if err != nil {
+		return "", err
+	}
+	names := []string{}
+	_, err = client.Do(req, &names, ExitWithErrorOnTimeout)
+	if err != nil {
+		return "", err
+	}
+	return names[0], nil
+}
+
+func TestTLSInMemory(t *testing.T) {
+	certPEM, keyPEM := newTestClientCert(t, "client-1")
+	server := newMutualTLSServer(certPEM)
+	defer server.Close()
+
+	config := GetValidConfigTest()
+	config.CertPEM, config.KeyPEM = certPEM, keyPEM
+	config.CACertPEM = serverCAPEM(server)
+	client := newLocalTestClient(t, server, config)
+	name, err := getClientCertName(client)
+	assert.Nil(t, err)
+	assert.Equal(t, "client-1", name)
+
+	// The server certificate is not trusted without the CA certificate
+	config = GetValidConfigTest()
+	config.CertPEM, config.KeyPEM = certPEM, keyPEM
+	client = newLocalTestClient(t, server, config)
+	_, err = getClientCertName(client)
+	assert.NotNil(t, err)
+
+	// The server does not support the minimum TLS version
+	config = GetValidConfigTest()
+	config.CertPEM, config.KeyPEM = certPEM, keyPEM
+	config.CACertPEM = serverCAPEM(server)
+	config.MinTLSVersion = tls.VersionTLS13
+	client = newLocalTestClient(t, server, config)
+	_, err = getClientCertName(client)
+	assert.NotNil(t, err)
+
+	// Invalid PEM data
+	config = GetValidConfigTest()
+	config.CACertPEM = []byte("not a certificate")
+	config.Host = server.URL
+	_, err = NewClient(&http.Client{Transport: &http.Transport{}}, config)
+	assert.NotNil(t, err)
+	config = GetValidConfigTest()
+	config.CertPEM, config.KeyPEM = certPEM, []byte("not a key")
+	config.Host = server.URL
+	_, err = NewClient(&http.Client{Transport: &http.Transport{}}, config)
+	assert.NotNil(t, err)
+}
+
+func TestTLSCertReload(t *testing.T) {
+	cert1, key1 := newTestClientCert(t, "client-1")
+	cert2, key2 := newTestClientCert(t, "client-2")
+	server := newMutualTLSServer(cert1, cert2)
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "whisk-tls")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	certFile, keyFile, caFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem"), filepath.Join(dir, "ca.pem")
+	assert.Nil(t, ioutil.WriteFile(certFile, cert1, 0600))
+	assert.Nil(t, ioutil.WriteFile(keyFile, key1, 0600))
+	assert.Nil(t, ioutil.WriteFile(caFile, serverCAPEM(server), 0600))
+
+	config := GetValidConfigTest()
+	config.Cert, config.Key, config.CACert = certFile, keyFile, caFile
+	client := newLocalTestClient(t, server, config)
+	name, err := getClientCertName(client)
+	assert.Nil(t, err)
+	assert.Equal(t, "client-1", name)
+
+	// Swap the certificate on disk; new connections use it
+	assert.Nil(t, ioutil.WriteFile(certFile, cert2, 0600))
+	assert.Nil(t, ioutil.WriteFile(keyFile, key2, 0600))
+	later := time.Now().Add(time.Minute)
+	os.Chtimes(certFile, later, later)
+	os.Chtimes(keyFile, later, later)
+	client.client.Transport.(*http.Transport).CloseIdleConnections()
+
+	name, err = getClientCertName(client)
+	assert.Nil(t, err)
+	assert.Equal(t, "client-2", name)
+}
+
+type countingRoundTripper struct {
+	next  http.RoundTripper
+	count int
+}
+
+func (rt *countingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.count++
+	return rt.next.RoundTrip(req)
+}
+
+func (rt *countingRoundTripper) BaseTransport() http.RoundTripper {
+	return rt.next
+}
+
+func (rt *countingRoundTripper) WithBaseTransport(transport http.RoundTripper) http.RoundTripper {
+	return &countingRoundTripper{next: transport}
+}
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestTLSKeepsProvidedTransport(t *testing.T) {
+	certPEM, keyPEM := newTestClientCert(t, "client-1")
+	server := newMutualTLSServer(certPEM)
+	defer server.Close()
+
+	provided := &http.Transport{MaxIdleConns: 7, TLSClientConfig: &tls.Config{ServerName: "example.com"}}
+	httpClient := &http.Client{Transport: provided}
+	config := GetValidConfigTest()
+	config.Host = server.URL
+	config.CertPEM, config.KeyPEM = certPEM, keyPEM
+	config.CACertPEM = serverCAPEM(server)
+	client, err := NewClient(httpClient, config)
+	assert.Nil(t, err)
+
+	// The provided http client and transport are copied, with their settings, and left untouched
+	assert.True(t, client.Transport != provided)
+	assert.True(t, httpClient.Transport == provided)
+	assert.True(t, client.client != httpClient)
+	assert.True(t, client.client.Transport == client.Transport)
+	assert.Equal(t, 7, client.Transport.MaxIdleConns)
+	assert.Equal(t, "example.com", client.Transport.TLSClientConfig.ServerName)
+	assert.Equal(t, 1, len(client.Transport.TLSClientConfig.Certificates))
+	assert.Nil(t, provided.TLSClientConfig.Certificates)
+	assert.Nil(t, provided.TLSClientConfig.RootCAs)
+
+	// The TLS configuration is applied to the base transport of a RoundTripperWrapper
+	counter := &countingRoundTripper{next: provided}
+	httpClient = &http.Client{Transport: counter}
+	client, err = NewClient(httpClient, config)
+	assert.Nil(t, err)
+	name, err := getClientCertName(client)
+	assert.Nil(t, err)
+	assert.Equal(t, "client-1", name)
+	assert.Equal(t, 0, counter.count)
+	wrapper, ok := client.client.Transport.(*countingRoundTripper)
+	if assert.True(t, ok) {
+		assert.Equal(t, 1, wrapper.count)
+		assert.True(t, wrapper.next == client.Transport)
+	}
+	assert.True(t, httpClient.Transport == counter)
+	assert.True(t, counter.next == provided)
+
+	// The TLS configuration cannot be applied to the other RoundTrippers
+	opaque := roundTripperFunc(http.DefaultTransport.RoundTrip)
+	httpClient = &http.Client{Transport: opaque}
+	config.Insecure = true
+	client, err = NewClient(httpClient, config)
+	assert.NotNil(t, err)
+	assert.Nil(t, client)
+
+	// Without TLS options, a custom RoundTripper is kept as is
+	httpClient = &http.Client{Transport: counter}
+	config = GetValidConfigTest()
+	client, err = NewClient(httpClient, config)
+	assert.Nil(t, err)
+	assert.True(t, client.client == httpClient)
+	assert.True(t, httpClient.Transport == counter)
+	assert.Nil(t, client.Transport)
+}
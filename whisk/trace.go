@@ -22,6 +22,7 @@ import (
 	"os"
 	"runtime"
 	"strings"
+	"sync/atomic"
 )
 
 type DebugLevel string
@@ -35,8 +36,9 @@ const (
 
 const MaxNameLen int = 25
 
-var isVerbose bool
-var isDebug bool
+// Accessed atomically, as they are read by the clients of any goroutine
+var isVerbose int32
+var isDebug int32
 
 func init() {
 	if len(os.Getenv("WSK_CLI_DEBUG")) > 0 { // Useful for tracing init() code, before parms are parsed
@@ -44,19 +46,30 @@ func init() {
 	}
 }
 
+// SetDebug enables the debug output of every client of the process, and of the package functions.  The debug output
+// of a single client is enabled by its Config.Debug instead.
 func SetDebug(b bool) {
-	isDebug = b
+	atomic.StoreInt32(&isDebug, boolToInt32(b))
 }
 
+// SetVerbose enables the verbose output of every client of the process.  The verbose output of a single client is
+// enabled by its Config.Verbose instead.
 func SetVerbose(b bool) {
-	isVerbose = b
+	atomic.StoreInt32(&isVerbose, boolToInt32(b))
 }
 
 func IsVerbose() bool {
-	return isVerbose || isDebug
+	return atomic.LoadInt32(&isVerbose) != 0 || IsDebug()
 }
 func IsDebug() bool {
-	return isDebug
+	return atomic.LoadInt32(&isDebug) != 0
+}
+
+func boolToInt32(b bool) int32 {
+	if b {
+		return 1
+	}
+	return 0
 }
 
 /* Function for tracing debug level messages to stdout
@@ -64,24 +77,28 @@ func IsDebug() bool {
    [file-or-function-name]:line-#:[DebugLevel] The formatted message without any appended \n
 */
 func Debug(dl DebugLevel, msgFormat string, args ...interface{}) {
-	if isDebug {
-		pc, file, line, _ := runtime.Caller(1)
-		fcn := runtime.FuncForPC(pc)
-		msg := fmt.Sprintf(msgFormat, args...)
-		fcnName := fcn.Name()
-
-		// Cobra command Run/RunE functions are anonymous, so the function name is unfriendly;
-		// use the file name instead
-		if strings.Contains(fcnName, "commands.glob.") || strings.Contains(fcnName, "whisk.glob.") {
-			fcnName = file
-		}
-
-		// Only interested in the the trailing function/file name characters
-		if len(fcnName) > MaxNameLen {
-			fcnName = fcnName[len(fcnName)-MaxNameLen:]
-		}
-		fmt.Printf("[%-25s]:%03d:[%3s] %v", fcnName, line, dl, msg)
+	if IsDebug() {
+		printDebug(2, dl, fmt.Sprintf(msgFormat, args...))
+	}
+}
+
+// printDebug prints a debug message to stdout, naming the function skip frames above printDebug in the stack.
+func printDebug(skip int, dl DebugLevel, msg string) {
+	pc, file, line, _ := runtime.Caller(skip)
+	fcn := runtime.FuncForPC(pc)
+	fcnName := fcn.Name()
+
+	// Cobra command Run/RunE functions are anonymous, so the function name is unfriendly;
+	// use the file name instead
+	if strings.Contains(fcnName, "commands.glob.") || strings.Contains(fcnName, "whisk.glob.") {
+		fcnName = file
+	}
+
+	// Only interested in the the trailing function/file name characters
+	if len(fcnName) > MaxNameLen {
+		fcnName = fcnName[len(fcnName)-MaxNameLen:]
 	}
+	fmt.Printf("[%-25s]:%03d:[%3s] %v", fcnName, line, dl, msg)
 }
 
 /* Function for tracing debug level messages to stdout
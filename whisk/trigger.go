@@ -18,6 +18,7 @@
 package whisk
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/apache/openwhisk-client-go/wski18n"
@@ -82,10 +83,14 @@ func (trigger Trigger) ToSummaryRowString() string {
 }
 
 func (s *TriggerService) List(options *TriggerListOptions) ([]Trigger, *http.Response, error) {
+	return s.ListContext(context.Background(), options)
+}
+
+func (s *TriggerService) ListContext(ctx context.Context, options *TriggerListOptions) ([]Trigger, *http.Response, error) {
 	route := "triggers"
-	routeUrl, err := addRouteOptions(route, options)
+	routeUrl, err := addRouteOptions(s.client, route, options)
 	if err != nil {
-		Debug(DbgError, "addRouteOptions(%s, %#v) error: '%s'\n", route, options, err)
+		debugOf(s.client, DbgError, "addRouteOptions(%s, %#v) error: '%s'\n", route, options, err)
 		errStr := wski18n.T("Unable to append options '{{.options}}' to URL route '{{.route}}': {{.err}}",
 			map[string]interface{}{"options": fmt.Sprintf("%#v", options), "route": route, "err": err})
 		werr := MakeWskError(errors.New(errStr), EXIT_CODE_ERR_GENERAL, DISPLAY_MSG, NO_DISPLAY_USAGE)
@@ -94,7 +99,7 @@ func (s *TriggerService) List(options *TriggerListOptions) ([]Trigger, *http.Res
 
 	req, err := s.client.NewRequestUrl("GET", routeUrl, nil, IncludeNamespaceInUrl, AppendOpenWhiskPathPrefix, EncodeBodyAsJson, AuthRequired)
 	if err != nil {
-		Debug(DbgError, "http.NewRequestUrl(GET, %s, nil, IncludeNamespaceInUrl, AppendOpenWhiskPathPrefix, EncodeBodyAsJson, AuthRequired); error: '%s'\n", route, err)
+		debugOf(s.client, DbgError, "http.NewRequestUrl(GET, %s, nil, IncludeNamespaceInUrl, AppendOpenWhiskPathPrefix, EncodeBodyAsJson, AuthRequired); error: '%s'\n", route, err)
 		errStr := wski18n.T("Unable to create HTTP request for GET '{{.route}}': {{.err}}",
 			map[string]interface{}{"route": route, "err": err})
 		werr := MakeWskErrorFromWskError(errors.New(errStr), err, EXIT_CODE_ERR_GENERAL, DISPLAY_MSG, NO_DISPLAY_USAGE)
@@ -102,9 +107,9 @@ func (s *TriggerService) List(options *TriggerListOptions) ([]Trigger, *http.Res
 	}
 
 	var triggers []Trigger
-	resp, err := s.client.Do(req, &triggers, ExitWithSuccessOnTimeout)
+	resp, err := doContext(s.client, ctx, req, &triggers, ExitWithSuccessOnTimeout)
 	if err != nil {
-		Debug(DbgError, "s.client.Do() error - HTTP req %s; error: '%s'\n", req.URL.String(), err)
+		debugOf(s.client, DbgError, "s.client.Do() error - HTTP req %s; error: '%s'\n", redactURL(s.client, req), err)
 		return nil, resp, err
 	}
 
@@ -112,6 +117,10 @@ func (s *TriggerService) List(options *TriggerListOptions) ([]Trigger, *http.Res
 }
 
 func (s *TriggerService) Insert(trigger *Trigger, overwrite bool) (*Trigger, *http.Response, error) {
+	return s.InsertContext(context.Background(), trigger, overwrite)
+}
+
+func (s *TriggerService) InsertContext(ctx context.Context, trigger *Trigger, overwrite bool) (*Trigger, *http.Response, error) {
 	// Encode resource name as a path (with no query params) before inserting it into the URI
 	// This way any '?' chars in the name won't be treated as the beginning of the query params
 	triggerName := (&url.URL{Path: trigger.Name}).String()
@@ -119,7 +128,7 @@ func (s *TriggerService) Insert(trigger *Trigger, overwrite bool) (*Trigger, *ht
 
 	routeUrl, err := url.Parse(route)
 	if err != nil {
-		Debug(DbgError, "url.Parse(%s) error: %s\n", route, err)
+		debugOf(s.client, DbgError, "url.Parse(%s) error: %s\n", route, err)
 		errStr := wski18n.T("Invalid request URL '{{.url}}': {{.err}}",
 			map[string]interface{}{"url": route, "err": err})
 		werr := MakeWskError(errors.New(errStr), EXIT_CODE_ERR_GENERAL, DISPLAY_MSG, NO_DISPLAY_USAGE)
@@ -128,7 +137,7 @@ func (s *TriggerService) Insert(trigger *Trigger, overwrite bool) (*Trigger, *ht
 
 	req, err := s.client.NewRequestUrl("PUT", routeUrl, trigger, IncludeNamespaceInUrl, AppendOpenWhiskPathPrefix, EncodeBodyAsJson, AuthRequired)
 	if err != nil {
-		Debug(DbgError, "http.NewRequestUrl(PUT, %s, %+v, IncludeNamespaceInUrl, AppendOpenWhiskPathPrefix, EncodeBodyAsJson, AuthRequired); error: '%s'\n", routeUrl, trigger, err)
+		debugOf(s.client, DbgError, "http.NewRequestUrl(PUT, %s, %+v, IncludeNamespaceInUrl, AppendOpenWhiskPathPrefix, EncodeBodyAsJson, AuthRequired); error: '%s'\n", routeUrl, trigger, err)
 		errStr := wski18n.T("Unable to create HTTP request for PUT '{{.route}}': {{.err}}",
 			map[string]interface{}{"route": routeUrl, "err": err})
 		werr := MakeWskErrorFromWskError(errors.New(errStr), err, EXIT_CODE_ERR_GENERAL, DISPLAY_MSG, NO_DISPLAY_USAGE)
@@ -136,9 +145,9 @@ func (s *TriggerService) Insert(trigger *Trigger, overwrite bool) (*Trigger, *ht
 	}
 
 	t := new(Trigger)
-	resp, err := s.client.Do(req, &t, ExitWithSuccessOnTimeout)
+	resp, err := doContext(s.client, ctx, req, &t, ExitWithSuccessOnTimeout)
 	if err != nil {
-		Debug(DbgError, "s.client.Do() error - HTTP req %s; error: '%s'\n", req.URL.String(), err)
+		debugOf(s.client, DbgError, "s.client.Do() error - HTTP req %s; error: '%s'\n", redactURL(s.client, req), err)
 		return nil, resp, err
 	}
 
@@ -147,6 +156,10 @@ func (s *TriggerService) Insert(trigger *Trigger, overwrite bool) (*Trigger, *ht
 }
 
 func (s *TriggerService) Get(triggerName string) (*Trigger, *http.Response, error) {
+	return s.GetContext(context.Background(), triggerName)
+}
+
+func (s *TriggerService) GetContext(ctx context.Context, triggerName string) (*Trigger, *http.Response, error) {
 	// Encode resource name as a path (with no query params) before inserting it into the URI
 	// This way any '?' chars in the name won't be treated as the beginning of the query params
 	triggerName = (&url.URL{Path: triggerName}).String()
@@ -154,7 +167,7 @@ func (s *TriggerService) Get(triggerName string) (*Trigger, *http.Response, erro
 
 	req, err := s.client.NewRequest("GET", route, nil, IncludeNamespaceInUrl)
 	if err != nil {
-		Debug(DbgError, "http.NewRequest(GET, %s); error: '%s'\n", route, err)
+		debugOf(s.client, DbgError, "http.NewRequest(GET, %s); error: '%s'\n", route, err)
 		errStr := wski18n.T("Unable to create HTTP request for GET '{{.route}}': {{.err}}",
 			map[string]interface{}{"route": route, "err": err})
 		werr := MakeWskErrorFromWskError(errors.New(errStr), err, EXIT_CODE_ERR_GENERAL, DISPLAY_MSG, NO_DISPLAY_USAGE)
@@ -162,9 +175,9 @@ func (s *TriggerService) Get(triggerName string) (*Trigger, *http.Response, erro
 	}
 
 	t := new(Trigger)
-	resp, err := s.client.Do(req, &t, ExitWithSuccessOnTimeout)
+	resp, err := doContext(s.client, ctx, req, &t, ExitWithSuccessOnTimeout)
 	if err != nil {
-		Debug(DbgError, "s.client.Do() error - HTTP req %s; error: '%s'\n", req.URL.String(), err)
+		debugOf(s.client, DbgError, "s.client.Do() error - HTTP req %s; error: '%s'\n", redactURL(s.client, req), err)
 		return nil, resp, err
 	}
 
@@ -173,6 +186,10 @@ func (s *TriggerService) Get(triggerName string) (*Trigger, *http.Response, erro
 }
 
 func (s *TriggerService) Delete(triggerName string) (*Trigger, *http.Response, error) {
+	return s.DeleteContext(context.Background(), triggerName)
+}
+
+func (s *TriggerService) DeleteContext(ctx context.Context, triggerName string) (*Trigger, *http.Response, error) {
 	// Encode resource name as a path (with no query params) before inserting it into the URI
 	// This way any '?' chars in the name won't be treated as the beginning of the query params
 	triggerName = (&url.URL{Path: triggerName}).String()
@@ -180,7 +197,7 @@ func (s *TriggerService) Delete(triggerName string) (*Trigger, *http.Response, e
 
 	req, err := s.client.NewRequest("DELETE", route, nil, IncludeNamespaceInUrl)
 	if err != nil {
-		Debug(DbgError, "http.NewRequest(DELETE, %s); error: '%s'\n", route, err)
+		debugOf(s.client, DbgError, "http.NewRequest(DELETE, %s); error: '%s'\n", route, err)
 		errStr := wski18n.T("Unable to create HTTP request for DELETE '{{.route}}': {{.err}}",
 			map[string]interface{}{"route": route, "err": err})
 		werr := MakeWskErrorFromWskError(errors.New(errStr), err, EXIT_CODE_ERR_GENERAL, DISPLAY_MSG, NO_DISPLAY_USAGE)
@@ -188,9 +205,9 @@ func (s *TriggerService) Delete(triggerName string) (*Trigger, *http.Response, e
 	}
 
 	t := new(Trigger)
-	resp, err := s.client.Do(req, &t, ExitWithSuccessOnTimeout)
+	resp, err := doContext(s.client, ctx, req, &t, ExitWithSuccessOnTimeout)
 	if err != nil {
-		Debug(DbgError, "s.client.Do() error - HTTP req %s; error: '%s'\n", req.URL.String(), err)
+		debugOf(s.client, DbgError, "s.client.Do() error - HTTP req %s; error: '%s'\n", redactURL(s.client, req), err)
 		return nil, resp, err
 	}
 
@@ -198,6 +215,10 @@ func (s *TriggerService) Delete(triggerName string) (*Trigger, *http.Response, e
 }
 
 func (s *TriggerService) Fire(triggerName string, payload interface{}) (*Trigger, *http.Response, error) {
+	return s.FireContext(context.Background(), triggerName, payload)
+}
+
+func (s *TriggerService) FireContext(ctx context.Context, triggerName string, payload interface{}) (*Trigger, *http.Response, error) {
 	// Encode resource name as a path (with no query params) before inserting it into the URI
 	// This way any '?' chars in the name won't be treated as the beginning of the query params
 	triggerName = (&url.URL{Path: triggerName}).String()
@@ -205,7 +226,7 @@ func (s *TriggerService) Fire(triggerName string, payload interface{}) (*Trigger
 
 	req, err := s.client.NewRequest("POST", route, payload, IncludeNamespaceInUrl)
 	if err != nil {
-		Debug(DbgError, " http.NewRequest(POST, %s); error: '%s'\n", route, err)
+		debugOf(s.client, DbgError, " http.NewRequest(POST, %s); error: '%s'\n", route, err)
 		errStr := wski18n.T("Unable to create HTTP request for POST '{{.route}}': {{.err}}",
 			map[string]interface{}{"route": route, "err": err})
 		werr := MakeWskErrorFromWskError(errors.New(errStr), err, EXIT_CODE_ERR_GENERAL, DISPLAY_MSG, NO_DISPLAY_USAGE)
@@ -213,9 +234,9 @@ func (s *TriggerService) Fire(triggerName string, payload interface{}) (*Trigger
 	}
 
 	t := new(Trigger)
-	resp, err := s.client.Do(req, &t, ExitWithSuccessOnTimeout)
+	resp, err := doContext(s.client, ctx, req, &t, ExitWithSuccessOnTimeout)
 	if err != nil {
-		Debug(DbgError, "s.client.Do() error - HTTP req %s; error: '%s'\n", req.URL.String(), err)
+		debugOf(s.client, DbgError, "s.client.Do() error - HTTP req %s; error: '%s'\n", redactURL(s.client, req), err)
 		return nil, resp, err
 	}
 
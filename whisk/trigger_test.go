@@ -20,6 +20,7 @@
 package whisk
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/stretchr/testify/assert"
@@ -129,3 +130,17 @@ func TestTriggerGet(t *testing.T) {
 	assert.Equal("triggers/testTrigger", triggerRequest.URL)
 	assert.Equal(expectedRules, trigger.Rules)
 }
+
+func TestTriggerGetContextWithoutDoContext(t *testing.T) {
+	var client ClientInterface = &MockTriggerClient{}
+	_, isContextClient := client.(ClientContextInterface)
+	assert.False(t, isContextClient)
+
+	var triggerService TriggerServiceInterface = &TriggerService{client: client}
+	triggerResponse.Body = TRIGGER_GET_NO_RULES
+	trigger, _, err := triggerService.(TriggerServiceContextInterface).GetContext(context.Background(), "testTrigger")
+	assert.Nil(t, err)
+	assert.Equal(t, "GET", triggerRequest.Method)
+	assert.Equal(t, "triggers/testTrigger", triggerRequest.URL)
+	assert.Equal(t, "testTrigger", trigger.Name)
+}
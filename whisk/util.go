@@ -46,12 +46,13 @@ type Printable interface {
 }
 
 // addOptions adds the parameters in opt as URL query parameters to s.  opt
-// must be a struct whose fields may contain "url" tags.
-func addRouteOptions(route string, options interface{}) (*url.URL, error) {
-	Debug(DbgInfo, "Adding options %+v to route '%s'\n", options, route)
+// must be a struct whose fields may contain "url" tags.  The route is traced by the client.
+func addRouteOptions(client ClientInterface, route string, options interface{}) (*url.URL, error) {
+	// The options, such as the API gateway access token, are only traced as the redacted query of the route
+	debugOf(client, DbgInfo, "Adding options to route '%s'\n", route)
 	u, err := url.Parse(route)
 	if err != nil {
-		Debug(DbgError, "url.Parse(%s) error: %s\n", route, err)
+		debugOf(client, DbgError, "url.Parse(%s) error: %s\n", route, err)
 		errStr := wski18n.T("Unable to parse URL '{{.route}}': {{.err}}",
 			map[string]interface{}{"route": route, "err": err})
 		werr := MakeWskError(errors.New(errStr), EXIT_CODE_ERR_GENERAL, DISPLAY_MSG, NO_DISPLAY_USAGE)
@@ -65,15 +66,16 @@ func addRouteOptions(route string, options interface{}) (*url.URL, error) {
 
 	qs, err := query.Values(options)
 	if err != nil {
-		Debug(DbgError, "query.Values(%#v) error: %s\n", options, err)
+		// The options, which may hold secrets, are not traced
+		debugOf(client, DbgError, "query.Values(%T) error: %s\n", options, err)
 		errStr := wski18n.T("Unable to process URL query options '{{.options}}': {{.err}}",
-			map[string]interface{}{"options": fmt.Sprintf("%#v", options), "err": err})
+			map[string]interface{}{"options": fmt.Sprintf("%T", options), "err": err})
 		werr := MakeWskError(errors.New(errStr), EXIT_CODE_ERR_GENERAL, DISPLAY_MSG, NO_DISPLAY_USAGE)
 		return nil, werr
 	}
 
 	u.RawQuery = qs.Encode()
-	Debug(DbgInfo, "Returning route options '%s' from input struct %+v\n", u.String(), options)
+	debugOf(client, DbgInfo, "Returning route options '%s'\n", redactorOf(client).url(u))
 	return u, nil
 }
 
@@ -17,10 +17,16 @@
 
 package whisk
 
+import (
+	"errors"
+	"net/http"
+)
+
 const EXIT_CODE_ERR_GENERAL int = 1
 const EXIT_CODE_ERR_USAGE int = 2
 const EXIT_CODE_ERR_NETWORK int = 3
 const EXIT_CODE_ERR_HTTP_RESP int = 4
+const EXIT_CODE_ERR_CREDENTIALS int = 5
 const NOT_ALLOWED int = 149
 const EXIT_CODE_TIMED_OUT int = 202
 const EXIT_CODE_NOT_FOUND int = 148
@@ -36,15 +42,29 @@ const APPLICATION_ERR bool = true
 const NO_APPLICATION_ERR bool = false
 const TIMED_OUT bool = true
 
+// Sentinel errors to test a WskError against with errors.Is, e.g. errors.Is(err, whisk.ErrNotFound)
+var (
+	ErrNotFound     = errors.New("resource not found")      // HTTP 404
+	ErrConflict     = errors.New("resource conflict")       // HTTP 409, i.e. the entity already exists
+	ErrUnauthorized = errors.New("authentication failed")   // HTTP 401
+	ErrForbidden    = errors.New("access denied")           // HTTP 403
+	ErrThrottled    = errors.New("too many requests")       // HTTP 429
+	ErrTimedOut     = errors.New("timed out")               // WskError.TimedOut, i.e. a blocking request is still processing
+	ErrApplication  = errors.New("application error")       // WskError.ApplicationError
+	ErrCredentials  = errors.New("credentials unavailable") // The CredentialProvider failed, so the request was not sent
+)
+
 type WskError struct {
-	RootErr          error // Parent error
-	ExitCode         int   // Error code to be returned to the OS
-	DisplayMsg       bool  // When true, the error message should be displayed to console
-	MsgDisplayed     bool  // When true, the error message has already been displayed, don't display it again
-	DisplayUsage     bool  // When true, the CLI usage should be displayed before exiting
-	DisplayPrefix    bool  // When true, the CLI will prefix an error message with "error: "
-	ApplicationError bool  // When true, the error is a result of an application failure
-	TimedOut         bool  // When True, the error is a result of a timeout
+	RootErr          error         // Parent error
+	ExitCode         int           // Error code to be returned to the OS
+	StatusCode       int           // HTTP status code of the response that caused the error; 0 when there is no response
+	DisplayMsg       bool          // When true, the error message should be displayed to console
+	MsgDisplayed     bool          // When true, the error message has already been displayed, don't display it again
+	DisplayUsage     bool          // When true, the CLI usage should be displayed before exiting
+	DisplayPrefix    bool          // When true, the CLI will prefix an error message with "error: "
+	ApplicationError bool          // When true, the error is a result of an application failure
+	TimedOut         bool          // When True, the error is a result of a timeout
+	Metadata         *CallMetadata // HTTP call that failed, with its transaction id; nil when the error is not from a call
 }
 
 /*
@@ -58,6 +78,35 @@ func (whiskError WskError) Error() string {
 	return whiskError.RootErr.Error()
 }
 
+// Unwrap returns the parent error.  For an error response from the server, the parent error is an
+// *ErrorResponse, which errors.As can retrieve to get the server's error code (transaction id).
+func (whiskError WskError) Unwrap() error {
+	return whiskError.RootErr
+}
+
+// Is reports whether the error matches one of the sentinel errors, such as ErrNotFound.
+func (whiskError WskError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return whiskError.StatusCode == http.StatusNotFound
+	case ErrConflict:
+		return whiskError.StatusCode == http.StatusConflict
+	case ErrUnauthorized:
+		return whiskError.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return whiskError.StatusCode == http.StatusForbidden
+	case ErrThrottled:
+		return whiskError.StatusCode == http.StatusTooManyRequests
+	case ErrTimedOut:
+		return whiskError.TimedOut
+	case ErrApplication:
+		return whiskError.ApplicationError
+	case ErrCredentials:
+		return whiskError.ExitCode == EXIT_CODE_ERR_CREDENTIALS
+	}
+	return false
+}
+
 /*
 Instantiate a WskError structure
 Parameters:
@@ -117,7 +166,10 @@ Parameters:
 */
 func MakeWskErrorFromWskError(baseError error, whiskError error, exitCode int, flags ...bool) (resWhiskError *WskError) {
 
-	// Get the exit code, and flags from the existing Whisk error
+	var statusCode int
+	var metadata *CallMetadata
+
+	// Get the exit code, status code, call metadata and flags from the existing Whisk error
 	if whiskError != nil {
 
 		// Ensure the Whisk error is a pointer
@@ -129,11 +181,16 @@ func MakeWskErrorFromWskError(baseError error, whiskError error, exitCode int, f
 		}
 
 		if resWhiskError != nil {
+			statusCode = resWhiskError.StatusCode
+			metadata = resWhiskError.Metadata
 			exitCode, flags = getWhiskErrorProperties(resWhiskError, flags...)
 		}
 	}
 
-	return MakeWskError(baseError, exitCode, flags...)
+	resWhiskError = MakeWskError(baseError, exitCode, flags...)
+	resWhiskError.StatusCode = statusCode
+	resWhiskError.Metadata = metadata
+	return resWhiskError
 }
 
 /*
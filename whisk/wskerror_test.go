@@ -0,0 +1,99 @@
+// +build unit
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package whisk
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newStatusServer(status int, body string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+}
+
+func TestWskErrorIs(t *testing.T) {
+	sentinels := map[int]error{
+		http.StatusNotFound:        ErrNotFound,
+		http.StatusConflict:        ErrConflict,
+		http.StatusUnauthorized:    ErrUnauthorized,
+		http.StatusForbidden:       ErrForbidden,
+		http.StatusTooManyRequests: ErrThrottled,
+	}
+
+	for status, sentinel := range sentinels {
+		server := newStatusServer(status, `{"error":"failed","code":"4f2d1a"}`)
+		client := newLocalTestClient(t, server, nil)
+
+		_, _, err := client.Triggers.Get("test")
+		assert.True(t, errors.Is(err, sentinel), "status %d is not %s", status, sentinel)
+		for _, other := range sentinels {
+			if other != sentinel {
+				assert.False(t, errors.Is(err, other), "status %d is %s", status, other)
+			}
+		}
+		server.Close()
+	}
+}
+
+func TestWskErrorIsApplicationAndTimedOut(t *testing.T) {
+	server := newStatusServer(http.StatusBadGateway, `{"response":{"status":"application error","success":false,"result":{"error":"boom"}}}`)
+	client := newLocalTestClient(t, server, nil)
+	_, _, err := client.Actions.Invoke("test", nil, true, false)
+	assert.True(t, errors.Is(err, ErrApplication))
+	assert.False(t, errors.Is(err, ErrTimedOut))
+	server.Close()
+
+	server = newStatusServer(http.StatusAccepted, `{"activationId":"f00ba7"}`)
+	client = newLocalTestClient(t, server, nil)
+	_, _, err = client.Actions.Invoke("test", nil, true, false)
+	assert.True(t, errors.Is(err, ErrTimedOut))
+	assert.False(t, errors.Is(err, ErrApplication))
+	server.Close()
+}
+
+func TestWskErrorAsErrorResponse(t *testing.T) {
+	server := newStatusServer(http.StatusConflict, `{"error":"resource already exists","code":"4f2d1a"}`)
+	defer server.Close()
+	client := newLocalTestClient(t, server, nil)
+
+	_, _, err := client.Rules.Insert(&Rule{Name: "test"}, false)
+	var errResp *ErrorResponse
+	assert.True(t, errors.As(err, &errResp))
+	assert.Equal(t, "4f2d1a", errResp.TransactionID())
+	assert.Equal(t, http.StatusConflict, errResp.Response.StatusCode)
+
+	var whiskErr *WskError
+	assert.True(t, errors.As(err, &whiskErr))
+	assert.Equal(t, http.StatusConflict, whiskErr.StatusCode)
+}
+
+func TestMakeWskErrorFromWskErrorKeepsStatusCode(t *testing.T) {
+	base := &WskError{RootErr: errors.New("not found"), ExitCode: EXIT_CODE_NOT_FOUND, StatusCode: http.StatusNotFound}
+	err := MakeWskErrorFromWskError(errors.New("wrapped"), base, EXIT_CODE_ERR_GENERAL)
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.Equal(t, EXIT_CODE_NOT_FOUND, err.ExitCode)
+}
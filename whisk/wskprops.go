@@ -18,13 +18,14 @@
 package whisk
 
 import (
-	"bufio"
 	"errors"
 	"fmt"
 	"github.com/apache/openwhisk-client-go/wski18n"
 	"io/ioutil"
 	"net/url"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
 )
 
@@ -39,6 +40,7 @@ const (
 	OPENWHISK_HOST       = "whisk.api.host.name"
 	DEFAULT_VERSION      = "v1"
 	DEFAULT_NAMESPACE    = "_"
+	DEFAULT_INSECURE     = "true" // For compatibility, the server certificates are not verified without INSECURE=false
 
 	APIGW_ACCESS_TOKEN = "APIGW_ACCESS_TOKEN"
 	APIGW_TENANT_ID    = "APIGW_TENANT_ID"
@@ -46,8 +48,11 @@ const (
 	APIVERSION         = "APIVERSION"
 	AUTH               = "AUTH"
 	CERT               = "CERT"
+	INSECURE           = "INSECURE" // "false" to verify the server certificates, which are not verified by default
 	KEY                = "KEY"
 	NAMESPACE          = "NAMESPACE"
+	PROFILE            = "PROFILE"     // Top-level property selecting the default profile
+	WSK_PROFILE        = "WSK_PROFILE" // Environment variable selecting the profile, overriding PROFILE
 
 	DEFAULT_SOURCE = "wsk props"
 	WSKPROP        = "wsk props"
@@ -62,9 +67,11 @@ type Wskprops struct {
 	AuthAPIGWKey   string
 	AuthKey        string
 	Cert           string
+	Insecure       string // Value of the INSECURE property; empty for the default, see INSECURE
 	Key            string
 	Namespace      string
 	Source         string
+	Profile        string // Name of the .wskprops profile the properties were read from; empty for the top-level properties
 }
 
 func GetUrlBase(host string) (*url.URL, error) {
@@ -92,11 +99,22 @@ func convertWskpropsToConfig(dep *Wskprops) *Config {
 	config.Cert = dep.Cert
 	config.Key = dep.Key
 	config.AuthToken = dep.AuthKey
+	config.ApigwAccessToken = dep.AuthAPIGWKey
+	config.ApigwTenantId = dep.APIGWTenantId
 
 	config.Version = dep.Apiversion
 	config.Verbose = false
 	config.Debug = false
-	config.Insecure = true
+	config.Insecure = DEFAULT_INSECURE == "true"
+	if len(dep.Insecure) > 0 {
+		if insecure, err := strconv.ParseBool(dep.Insecure); err == nil {
+			config.Insecure = insecure
+		} else {
+			// A typo must not turn off the verification of the server certificates
+			Debug(DbgWarn, "Invalid %s value '%s'; the server certificates are verified\n", INSECURE, dep.Insecure)
+			config.Insecure = false
+		}
+	}
 
 	return &config
 }
@@ -158,45 +176,156 @@ type Properties interface {
 }
 
 type PropertiesImp struct {
-	OsPackage OSPackage
+	OsPackage   OSPackage
+	Interpolate bool // Replace ${NAME} in the .wskprops values with the environment variable NAME
+	Quotes      bool // Read the quoted .wskprops values, see PropertiesReader
+	Escapes     bool // Read the backslashes of the .wskprops file as escapes, see PropertiesReader
+}
+
+// reader returns the PropertiesReader of the .wskprops file, which looks up the environment with OsPackage.
+func (pi PropertiesImp) reader() PropertiesReader {
+	return PropertiesReader{
+		Escapes:     pi.Escapes,
+		Quotes:      pi.Quotes,
+		Interpolate: pi.Interpolate,
+		Getenv: func(key string) string {
+			return pi.OsPackage.Getenv(key, "")
+		},
+	}
 }
 
 func (pi PropertiesImp) GetPropsFromWskprops(path string) *Wskprops {
+	dep, err := pi.GetPropsFromWskpropsProfile(path, "")
+	if err != nil {
+		Debug(DbgWarn, "Unable to read the .wskprops properties: %s\n", err)
+	}
+	return dep
+}
+
+/*
+Reads the properties of a .wskprops file, from the given profile.  A .wskprops file may define named profiles in
+INI-style sections, whose properties override the top-level properties:
+
+	APIHOST=openwhisk.example.com
+	PROFILE=dev
+
+	[dev]
+	AUTH=...
+
+	[prod]
+	AUTH=...
+	NAMESPACE=production
+
+When profile is empty, the profile named by the WSK_PROFILE environment variable is read, or else the one named by
+the top-level PROFILE property.  When no profile is selected, only the top-level properties are read.  Selecting a
+profile that is not defined is an error, and none of the file's properties are returned then.
+*/
+func (pi PropertiesImp) GetPropsFromWskpropsProfile(path string, profile string) (*Wskprops, error) {
 	dep := GetDefaultWskprops(WSKPROP)
 
-	var wskpropsPath string
-	if path != "" {
-		wskpropsPath = path
-	} else {
-		wskpropsPath = pi.OsPackage.Getenv(HOMEPATH, "") + "/" + DEFAULT_LOCAL_CONFIG
+	results, profile, err := pi.readWskprops(path, profile)
+	if err != nil {
+		return dep, err
 	}
-	results, err := ReadProps(wskpropsPath)
+	dep.Profile = profile
 
-	if err == nil {
+	dep.APIHost = GetValue(results, APIHOST, dep.APIHost)
 
-		dep.APIHost = GetValue(results, APIHOST, dep.APIHost)
+	dep.AuthKey = GetValue(results, AUTH, dep.AuthKey)
+	dep.Namespace = GetValue(results, NAMESPACE, dep.Namespace)
+	dep.AuthAPIGWKey = GetValue(results, APIGW_ACCESS_TOKEN, dep.AuthAPIGWKey)
+	dep.APIGWTenantId = GetValue(results, APIGW_TENANT_ID, dep.APIGWTenantId)
+	if len(dep.AuthKey) > 0 {
+		dep.APIGWSpaceSuid = strings.Split(dep.AuthKey, ":")[0]
+	}
+	dep.Apiversion = GetValue(results, APIVERSION, dep.Apiversion)
+	dep.Key = GetValue(results, KEY, dep.Key)
+	dep.Cert = GetValue(results, CERT, dep.Cert)
+	dep.Insecure = GetValue(results, INSECURE, dep.Insecure)
 
-		dep.AuthKey = GetValue(results, AUTH, dep.AuthKey)
-		dep.Namespace = GetValue(results, NAMESPACE, dep.Namespace)
-		dep.AuthAPIGWKey = GetValue(results, APIGW_ACCESS_TOKEN, dep.AuthAPIGWKey)
-		dep.APIGWTenantId = GetValue(results, APIGW_TENANT_ID, dep.APIGWTenantId)
-		if len(dep.AuthKey) > 0 {
-			dep.APIGWSpaceSuid = strings.Split(dep.AuthKey, ":")[0]
+	return dep, nil
+}
+
+// readWskprops returns the properties of a .wskprops file that apply to a profile, and the name of the profile, as
+// described for GetPropsFromWskpropsProfile.  A file that cannot be read has no properties, and the malformed lines
+// of a file are skipped; only an undefined profile is an error.
+func (pi PropertiesImp) readWskprops(path string, profile string) (map[string]string, string, error) {
+	sections, err := pi.readSections(path)
+	if err != nil {
+		Debug(DbgWarn, "Unable to read the .wskprops file: %s\n", err)
+		sections = map[string]map[string]string{"": {}}
+	}
+	results := sections[""]
+	if len(profile) == 0 {
+		profile = pi.OsPackage.Getenv(WSK_PROFILE, GetValue(results, PROFILE, ""))
+	}
+
+	if len(profile) > 0 {
+		profileResults, ok := sections[profile]
+		if !ok {
+			errStr := wski18n.T("The profile '{{.profile}}' is not defined in '{{.path}}'",
+				map[string]interface{}{"profile": profile, "path": pi.wskpropsPath(path)})
+			return nil, "", MakeWskError(errors.New(errStr), EXIT_CODE_ERR_GENERAL, DISPLAY_MSG, NO_DISPLAY_USAGE)
+		}
+		for key, value := range profileResults {
+			results[key] = value
 		}
-		dep.Apiversion = GetValue(results, APIVERSION, dep.Apiversion)
-		dep.Key = GetValue(results, KEY, dep.Key)
-		dep.Cert = GetValue(results, CERT, dep.Cert)
 	}
 
-	return dep
+	return results, profile, nil
+}
+
+// ListProfiles returns the names of the profiles defined in a .wskprops file, sorted, and the name of the profile
+// selected by default (see GetPropsFromWskpropsProfile), if any.
+func (pi PropertiesImp) ListProfiles(path string) ([]string, string, error) {
+	sections, err := pi.readSections(path)
+	if err != nil {
+		return nil, "", err
+	}
+
+	profiles := []string{}
+	for name := range sections {
+		if len(name) > 0 {
+			profiles = append(profiles, name)
+		}
+	}
+	sort.Strings(profiles)
+
+	return profiles, pi.OsPackage.Getenv(WSK_PROFILE, GetValue(sections[""], PROFILE, "")), nil
+}
+
+// readSections reads the sections of a .wskprops file.  The malformed lines are only reported as warnings, so that
+// they do not discard the valid lines of the file.
+func (pi PropertiesImp) readSections(path string) (map[string]map[string]string, error) {
+	sections, err := pi.reader().ReadSections(pi.wskpropsPath(path))
+	var propErr *PropertiesError
+	if errors.As(err, &propErr) {
+		Debug(DbgWarn, "Skipping the malformed lines of the .wskprops file: %s\n", propErr)
+		return sections, nil
+	}
+	return sections, err
+}
+
+func (pi PropertiesImp) wskpropsPath(path string) string {
+	if path != "" {
+		return path
+	}
+	return pi.OsPackage.Getenv(HOMEPATH, "") + "/" + DEFAULT_LOCAL_CONFIG
 }
 
 func (pi PropertiesImp) GetPropsFromWhiskProperties() *Wskprops {
 	dep := GetDefaultWskprops(WHISK_PROPERTY)
 	path := pi.OsPackage.Getenv(OPENWHISK_HOME, "") + "/" + OPENWHISK_PROPERTIES
-	results, err := ReadProps(path)
-
-	if err == nil {
+	// whisk.properties is a Java properties file, written with backslash escapes and line continuations
+	sections, err := PropertiesReader{Escapes: true}.ReadSections(path)
+	results := sections[""]
+
+	// The malformed lines of the file are skipped
+	var propErr *PropertiesError
+	if errors.As(err, &propErr) {
+		Debug(DbgWarn, "Skipping the malformed lines of the whisk.properties file: %s\n", propErr)
+	}
+	if err == nil || propErr != nil {
 		// TODO Determine why we have a hardcoed "test.auth" file here, is this only for unit tests? documented?
 		authPath := GetValue(results, TEST_AUTH_FILE, "")
 		b, err := ioutil.ReadFile(authPath)
@@ -275,6 +404,28 @@ func GetWskpropsConfig(path string) (*Config, error) {
 	return GetConfigFromWskprops(pi, path)
 }
 
+// GetWskpropsConfigForProfile returns the configuration of a profile of the .wskprops file under the HOME directory;
+// an empty profile selects the default profile.
+func GetWskpropsConfigForProfile(profile string) (*Config, error) {
+	pi := PropertiesImp{
+		OsPackage: OSPackageImp{},
+	}
+	dep, err := pi.GetPropsFromWskpropsProfile("", profile)
+	if err == nil {
+		err = ValidateWskprops(dep)
+	}
+	return convertWskpropsToConfig(dep), err
+}
+
+// ListWskpropsProfiles returns the names of the profiles defined in the .wskprops file under the HOME directory,
+// and the name of the profile selected by default, if any.
+func ListWskpropsProfiles() ([]string, string, error) {
+	pi := PropertiesImp{
+		OsPackage: OSPackageImp{},
+	}
+	return pi.ListProfiles("")
+}
+
 func GetDefaultWskprops(source string) *Wskprops {
 	if len(source) == 0 {
 		source = DEFAULT_SOURCE
@@ -284,6 +435,7 @@ func GetDefaultWskprops(source string) *Wskprops {
 		APIHost:        "",
 		AuthKey:        "",
 		Namespace:      DEFAULT_NAMESPACE,
+		Insecure:       DEFAULT_INSECURE,
 		AuthAPIGWKey:   "",
 		APIGWTenantId:  "",
 		APIGWSpaceSuid: "",
@@ -303,33 +455,19 @@ func GetValue(StoredValues map[string]string, key string, defaultvalue string) s
 	}
 }
 
+// ReadProps returns the top-level properties of a properties file, i.e. the ones before the first [profile] section.
+// The malformed lines are skipped, and reported as an error along with the properties of the other lines.
 func ReadProps(path string) (map[string]string, error) {
-
-	props := map[string]string{}
-
-	file, err := os.Open(path)
-	if err != nil {
-		return props, err
-	}
-	defer file.Close()
-
-	lines := []string{}
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		lines = append(lines, scanner.Text())
-	}
-
-	props = map[string]string{}
-	for _, line := range lines {
-		kv := strings.Split(line, "=")
-		if len(kv) != 2 {
-			continue
-		}
-		key := strings.TrimSpace(kv[0])
-		value := strings.TrimSpace(kv[1])
-		props[key] = value
+	sections, err := ReadPropsSections(path)
+	if sections == nil {
+		return map[string]string{}, err
 	}
+	return sections[""], err
+}
 
-	return props, nil
-
+// ReadPropsSections returns the properties of a properties file by section.  The top-level properties, before the
+// first [name] section header, are returned as the "" section, which is always present when the file can be read.
+// The backslashes are part of the keys and values.  See PropertiesReader for the syntax of the file.
+func ReadPropsSections(path string) (map[string]map[string]string, error) {
+	return PropertiesReader{}.ReadSections(path)
 }
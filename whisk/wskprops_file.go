@@ -0,0 +1,274 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package whisk
+
+import (
+	"errors"
+	"github.com/apache/openwhisk-client-go/wski18n"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Permissions of a saved .wskprops file, which holds AUTH keys
+const WSKPROPS_FILE_MODE os.FileMode = 0600
+
+// WskpropsFile is an editable .wskprops file.  Setting and unsetting properties only changes the lines of these
+// properties: the comments, the ordering, the unknown properties and the line endings of the file are preserved
+// when it is saved.  As with PropertiesImp, the backslashes of the file are part of the values unless Escapes is set.
+type WskpropsFile struct {
+	Path    string
+	Escapes bool
+	lines   []string
+	newline string // Line ending of the file, "\r\n" for a file with Windows line endings
+}
+
+// LoadWskpropsFile reads a .wskprops file for editing; an empty path is the .wskprops file under the HOME
+// directory.  A file that does not exist yet is loaded empty, and created when it is saved.
+func LoadWskpropsFile(path string) (*WskpropsFile, error) {
+	pi := PropertiesImp{
+		OsPackage: OSPackageImp{},
+	}
+	file := &WskpropsFile{Path: pi.wskpropsPath(path), newline: "\n"}
+
+	data, err := ioutil.ReadFile(file.Path)
+	if err != nil && !os.IsNotExist(err) {
+		Debug(DbgError, "ioutil.ReadFile(%s) error: %s\n", file.Path, err)
+		return nil, MakeWskError(err, EXIT_CODE_ERR_GENERAL, DISPLAY_MSG, NO_DISPLAY_USAGE)
+	}
+
+	if strings.Contains(string(data), "\r\n") {
+		file.newline = "\r\n"
+	}
+	text := strings.Replace(string(data), "\r\n", "\n", -1)
+	if len(text) > 0 {
+		file.lines = strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+	}
+	return file, nil
+}
+
+// Get returns the value of a property of a profile; an empty profile is the top-level properties.
+func (f *WskpropsFile) Get(profile string, key string) (string, bool) {
+	start, end, ok := f.section(profile)
+	if !ok {
+		return "", false
+	}
+	// As when the file is read, the last occurrence of a property wins
+	properties := f.properties(start, end)
+	for i := len(properties) - 1; i >= 0; i-- {
+		if properties[i].key == key {
+			_, value, err := f.reader().parseProperty(properties[i].line)
+			return value, err == nil
+		}
+	}
+	return "", false
+}
+
+// Set sets a property of a profile; an empty profile is the top-level properties.  An existing property is
+// updated in place, a new one is added at the end of its profile, and a new profile at the end of the file.
+// With Escapes, the value is escaped as needed, so that it is read back unchanged; without it, a value that would
+// not be read back unchanged, with line breaks or surrounding whitespace, is an error.
+func (f *WskpropsFile) Set(profile string, key string, value string) error {
+	if len(key) == 0 || strings.ContainsAny(key, "=: \t\f\r\n\\") || strings.ContainsAny(key[:1], "#![") {
+		errStr := wski18n.T("Invalid property name '{{.key}}'", map[string]interface{}{"key": key})
+		return MakeWskError(errors.New(errStr), EXIT_CODE_ERR_GENERAL, DISPLAY_MSG, NO_DISPLAY_USAGE)
+	}
+	if strings.ContainsAny(profile, "[]\r\n") {
+		errStr := wski18n.T("Invalid profile name '{{.profile}}'", map[string]interface{}{"profile": profile})
+		return MakeWskError(errors.New(errStr), EXIT_CODE_ERR_GENERAL, DISPLAY_MSG, NO_DISPLAY_USAGE)
+	}
+	line := key + "=" + value
+	if f.Escapes {
+		line = key + "=" + escapePropertyValue(value)
+	} else if strings.ContainsAny(value, "\r\n") || strings.TrimSpace(value) != value {
+		errStr := wski18n.T("The value of '{{.key}}' cannot be written without escapes", map[string]interface{}{"key": key})
+		return MakeWskError(errors.New(errStr), EXIT_CODE_ERR_GENERAL, DISPLAY_MSG, NO_DISPLAY_USAGE)
+	}
+
+	start, end, ok := f.section(profile)
+	if !ok {
+		if len(f.lines) > 0 && len(strings.TrimSpace(f.lines[len(f.lines)-1])) > 0 {
+			f.lines = append(f.lines, "")
+		}
+		f.lines = append(f.lines, "["+profile+"]", line)
+		return nil
+	}
+
+	properties := f.properties(start, end)
+	for i := len(properties) - 1; i >= 0; i-- {
+		if properties[i].key == key {
+			f.replace(properties[i].first, properties[i].last+1, line)
+			return nil
+		}
+	}
+
+	// Add the property after the last non-blank line of the section, before the blank lines separating it from the next one
+	at := end
+	for at > start && len(strings.TrimSpace(f.lines[at-1])) == 0 {
+		at--
+	}
+	f.replace(at, at, line)
+	return nil
+}
+
+// Unset removes every occurrence of a property from a profile, and returns whether the property was set.
+func (f *WskpropsFile) Unset(profile string, key string) bool {
+	start, end, ok := f.section(profile)
+	if !ok {
+		return false
+	}
+
+	removed := false
+	properties := f.properties(start, end)
+	for i := len(properties) - 1; i >= 0; i-- {
+		if properties[i].key == key {
+			f.replace(properties[i].first, properties[i].last+1)
+			removed = true
+		}
+	}
+	return removed
+}
+
+// Save writes the file atomically: it is written to a temporary file in the same directory, which then replaces
+// it.  When the file is a symbolic link, e.g. to a dotfiles repository, the file it links to is replaced instead,
+// and the link is kept.  The saved file is only readable and writable by its owner.
+func (f *WskpropsFile) Save() error {
+	newline := f.newline
+	if len(newline) == 0 {
+		newline = "\n"
+	}
+	content := ""
+	if len(f.lines) > 0 {
+		content = strings.Join(f.lines, newline) + newline
+	}
+
+	path, err := f.savePath()
+	if err != nil {
+		Debug(DbgError, "filepath.EvalSymlinks(%s) error: %s\n", f.Path, err)
+		return MakeWskError(err, EXIT_CODE_ERR_GENERAL, DISPLAY_MSG, NO_DISPLAY_USAGE)
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		Debug(DbgError, "ioutil.TempFile(%s) error: %s\n", filepath.Dir(path), err)
+		return MakeWskError(err, EXIT_CODE_ERR_GENERAL, DISPLAY_MSG, NO_DISPLAY_USAGE)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err = tmp.Chmod(WSKPROPS_FILE_MODE); err == nil {
+		if _, err = tmp.WriteString(content); err == nil {
+			err = tmp.Sync()
+		}
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		Debug(DbgError, "Unable to save '%s': %s\n", path, err)
+		return MakeWskError(err, EXIT_CODE_ERR_GENERAL, DISPLAY_MSG, NO_DISPLAY_USAGE)
+	}
+
+	Debug(DbgInfo, "Saved '%s'\n", path)
+	return nil
+}
+
+// savePath returns the file replaced by Save: Path, or the file it links to.  A file that does not exist yet is
+// created at Path, but a link to a missing file is an error rather than being replaced.
+func (f *WskpropsFile) savePath() (string, error) {
+	path, err := filepath.EvalSymlinks(f.Path)
+	if os.IsNotExist(err) {
+		if _, lstatErr := os.Lstat(f.Path); os.IsNotExist(lstatErr) {
+			return f.Path, nil
+		}
+	}
+	return path, err
+}
+
+// section returns the range of lines of a profile, after its header; the top-level properties are the lines
+// before the first header.
+func (f *WskpropsFile) section(profile string) (int, int, bool) {
+	start, found := 0, len(profile) == 0
+	for i, line := range f.lines {
+		name, isHeader := parseSectionHeader(line)
+		if !isHeader {
+			continue
+		}
+		if found {
+			return start, i, true
+		}
+		if name == profile {
+			start, found = i+1, true
+		}
+	}
+	return start, len(f.lines), found
+}
+
+// parseSectionHeader returns the profile name of a "[name]" line.
+func parseSectionHeader(line string) (string, bool) {
+	trimmed := strings.TrimSpace(line)
+	if strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]") {
+		return strings.TrimSpace(trimmed[1 : len(trimmed)-1]), true
+	}
+	return "", false
+}
+
+// propertyLines locates a property of the file, which spans several lines when they are continued
+type propertyLines struct {
+	first, last int
+	key         string
+	line        string // Logical line of the property, with the continuations joined
+}
+
+// properties returns the properties defined in a range of lines.
+func (f *WskpropsFile) properties(start int, end int) []propertyLines {
+	properties := []propertyLines{}
+	for i := start; i < end; i++ {
+		line := strings.TrimLeft(f.lines[i], " \t\f")
+		if len(line) == 0 || line[0] == '#' || line[0] == '!' {
+			continue
+		}
+
+		property := propertyLines{first: i}
+		for f.reader().continuesLine(line) && i+1 < end {
+			i++
+			line = line[:len(line)-1] + strings.TrimLeft(f.lines[i], " \t\f")
+		}
+		property.last, property.line = i, line
+
+		key, _, err := f.reader().unescape(line[:f.reader().keyEnd(line)], false)
+		if err == nil {
+			property.key = key
+			properties = append(properties, property)
+		}
+	}
+	return properties
+}
+
+func (f *WskpropsFile) reader() PropertiesReader {
+	return PropertiesReader{Escapes: f.Escapes}
+}
+
+// replace replaces the lines [from, to) of the file with the given lines.
+func (f *WskpropsFile) replace(from int, to int, lines ...string) {
+	tail := append(lines, f.lines[to:]...)
+	f.lines = append(f.lines[:from], tail...)
+}
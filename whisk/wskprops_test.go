@@ -23,7 +23,9 @@ import (
 	"bufio"
 	"fmt"
 	"github.com/stretchr/testify/assert"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -294,6 +296,66 @@ func TestGetPropsFromWskprops(t *testing.T) {
 	DeleteFile(DEFAULT_LOCAL_CONFIG)
 }
 
+func TestGetPropsFromWskpropsProfiles(t *testing.T) {
+	lines := []string{
+		APIHOST + "=" + EXPECTED_HOST,
+		AUTH + "=" + EXPECTED_TEST_AUTH_KEY,
+		PROFILE + "=dev",
+		"",
+		"[dev]",
+		NAMESPACE + "=" + EXPECTED_NAMESPACE_LOCAL_CONF,
+		"",
+		"[prod]",
+		APIHOST + "=" + EXPECTED_API_HOST_LOCAL_CONF,
+		AUTH + "=" + EXPECTED_TEST_AUTH_KEY_LOCAL_CONF,
+	}
+	CreateFile(lines, DEFAULT_LOCAL_CONFIG)
+	defer DeleteFile(DEFAULT_LOCAL_CONFIG)
+
+	fakeOSPackage := FakeOSPackage{
+		StoredValues: map[string]string{
+			HOMEPATH: getCurrentDir(),
+		},
+	}
+	pi := PropertiesImp{
+		OsPackage: fakeOSPackage,
+	}
+
+	// The PROFILE property selects the default profile, which overrides the top-level properties
+	dep := pi.GetPropsFromWskprops("")
+	assert.Equal(t, "dev", dep.Profile)
+	assert.Equal(t, EXPECTED_HOST, dep.APIHost)
+	assert.Equal(t, EXPECTED_TEST_AUTH_KEY, dep.AuthKey)
+	assert.Equal(t, EXPECTED_NAMESPACE_LOCAL_CONF, dep.Namespace)
+
+	dep, err := pi.GetPropsFromWskpropsProfile("", "prod")
+	assert.Nil(t, err)
+	assert.Equal(t, "prod", dep.Profile)
+	assert.Equal(t, EXPECTED_API_HOST_LOCAL_CONF, dep.APIHost)
+	assert.Equal(t, EXPECTED_TEST_AUTH_KEY_LOCAL_CONF, dep.AuthKey)
+	assert.Equal(t, DEFAULT_NAMESPACE, dep.Namespace)
+
+	_, err = pi.GetPropsFromWskpropsProfile("", "staging")
+	assert.NotNil(t, err)
+
+	// WSK_PROFILE overrides the PROFILE property
+	fakeOSPackage.StoredValues[WSK_PROFILE] = "prod"
+	dep = pi.GetPropsFromWskprops("")
+	assert.Equal(t, "prod", dep.Profile)
+	assert.Equal(t, EXPECTED_API_HOST_LOCAL_CONF, dep.APIHost)
+
+	profiles, selected, err := pi.ListProfiles("")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"dev", "prod"}, profiles)
+	assert.Equal(t, "prod", selected)
+
+	// The top-level properties of a flat file are not affected by the sections
+	props, err := ReadProps(getCurrentDir() + "/" + DEFAULT_LOCAL_CONFIG)
+	assert.Nil(t, err)
+	assert.Equal(t, EXPECTED_HOST, props[APIHOST])
+	assert.Equal(t, 3, len(props))
+}
+
 func TestGetDefaultConfigFromProperties(t *testing.T) {
 	fakeProperties := FakePropertiesImp{
 		StoredValues_LOCAL_CONF: map[string]string{
@@ -851,3 +913,116 @@ func TestGetWskPropFromWhiskProperty(t *testing.T) {
 	assert.Contains(t, err.Error(), MISSING_URL_MESSAGE)
 
 }
+
+func TestWskpropsFileWriteBack(t *testing.T) {
+	lines := []string{
+		"# OpenWhisk CLI properties",
+		APIHOST + "=" + EXPECTED_HOST,
+		"UNKNOWN=value",
+		AUTH + "=" + EXPECTED_TEST_AUTH_KEY,
+		"",
+		"[prod]",
+		"# Production",
+		APIHOST + "=" + EXPECTED_API_HOST_LOCAL_CONF,
+	}
+	path := getCurrentDir() + "/" + DEFAULT_LOCAL_CONFIG
+	CreateFile(lines, path)
+	defer DeleteFile(path)
+
+	file, err := LoadWskpropsFile(path)
+	assert.Nil(t, err)
+	value, ok := file.Get("prod", APIHOST)
+	assert.True(t, ok)
+	assert.Equal(t, EXPECTED_API_HOST_LOCAL_CONF, value)
+
+	assert.Nil(t, file.Set("", APIHOST, "localhost"))
+	assert.Nil(t, file.Set("", NAMESPACE, EXPECTED_NAMESPACE_LOCAL_CONF))
+	assert.Nil(t, file.Set("prod", APIGW_ACCESS_TOKEN, "dG9rZW4="))
+	assert.Nil(t, file.Set("dev", AUTH, EXPECTED_TEST_AUTH_KEY_LOCAL_CONF))
+	assert.True(t, file.Unset("", AUTH))
+	assert.False(t, file.Unset("prod", AUTH))
+	assert.NotNil(t, file.Set("", "BAD=KEY", "value"))
+	assert.NotNil(t, file.Set("", "BAD KEY", "value"))
+	assert.Nil(t, file.Set("", CERT, `C:\Users\me\cert.pem`))
+	assert.NotNil(t, file.Set("prod", "NOTE", " multi\nline "))
+	file.Escapes = true
+	assert.Nil(t, file.Set("prod", "NOTE", " multi\nline "))
+	assert.Nil(t, file.Save())
+
+	expected := "# OpenWhisk CLI properties\n" +
+		APIHOST + "=localhost\n" +
+		"UNKNOWN=value\n" +
+		NAMESPACE + "=" + EXPECTED_NAMESPACE_LOCAL_CONF + "\n" +
+		CERT + `=C:\Users\me\cert.pem` + "\n" +
+		"\n" +
+		"[prod]\n" +
+		"# Production\n" +
+		APIHOST + "=" + EXPECTED_API_HOST_LOCAL_CONF + "\n" +
+		APIGW_ACCESS_TOKEN + "=dG9rZW4=\n" +
+		"NOTE=\\ multi\\nline\\ \n" +
+		"\n" +
+		"[dev]\n" +
+		AUTH + "=" + EXPECTED_TEST_AUTH_KEY_LOCAL_CONF + "\n"
+	data, err := ioutil.ReadFile(path)
+	assert.Nil(t, err)
+	assert.Equal(t, expected, string(data))
+
+	info, err := os.Stat(path)
+	assert.Nil(t, err)
+	assert.Equal(t, WSKPROPS_FILE_MODE, info.Mode().Perm())
+
+	// The saved properties are read back, including values containing '='
+	sections, err := ReadPropsSections(path)
+	assert.Nil(t, err)
+	assert.Equal(t, "localhost", sections[""][APIHOST])
+	assert.Equal(t, "dG9rZW4=", sections["prod"][APIGW_ACCESS_TOKEN])
+	assert.Equal(t, EXPECTED_TEST_AUTH_KEY_LOCAL_CONF, sections["dev"][AUTH])
+	assert.Equal(t, `C:\Users\me\cert.pem`, sections[""][CERT])
+	sections, err = PropertiesReader{Escapes: true}.ReadSections(path)
+	assert.Nil(t, err)
+	assert.Equal(t, " multi\nline ", sections["prod"]["NOTE"])
+	value, _ = file.Get("prod", "NOTE")
+	assert.Equal(t, " multi\nline ", value)
+
+	// A missing file is created on save
+	DeleteFile(path)
+	file, err = LoadWskpropsFile(path)
+	assert.Nil(t, err)
+	assert.Nil(t, file.Set("", AUTH, EXPECTED_TEST_AUTH_KEY))
+	assert.Nil(t, file.Save())
+	data, err = ioutil.ReadFile(path)
+	assert.Nil(t, err)
+	assert.Equal(t, AUTH+"="+EXPECTED_TEST_AUTH_KEY+"\n", string(data))
+}
+
+func TestWskpropsFileSaveKeepsLinkAndLineEndings(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wskprops")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	// A .wskprops linked to a file of a dotfiles repository, with Windows line endings
+	target := filepath.Join(dir, "dotfiles", "wskprops")
+	assert.Nil(t, os.MkdirAll(filepath.Dir(target), 0700))
+	assert.Nil(t, ioutil.WriteFile(target, []byte("# Dotfiles\r\n"+APIHOST+"="+EXPECTED_HOST+"\r\n"), 0600))
+	path := filepath.Join(dir, DEFAULT_LOCAL_CONFIG)
+	assert.Nil(t, os.Symlink(filepath.Join("dotfiles", "wskprops"), path))
+
+	file, err := LoadWskpropsFile(path)
+	assert.Nil(t, err)
+	assert.Nil(t, file.Set("", AUTH, EXPECTED_TEST_AUTH_KEY))
+	assert.Nil(t, file.Save())
+
+	info, err := os.Lstat(path)
+	assert.Nil(t, err)
+	assert.True(t, info.Mode()&os.ModeSymlink != 0, "the link was replaced")
+	data, err := ioutil.ReadFile(target)
+	assert.Nil(t, err)
+	assert.Equal(t, "# Dotfiles\r\n"+APIHOST+"="+EXPECTED_HOST+"\r\n"+AUTH+"="+EXPECTED_TEST_AUTH_KEY+"\r\n", string(data))
+
+	// A link to a missing file is not replaced
+	assert.Nil(t, os.Remove(target))
+	assert.NotNil(t, file.Save())
+	info, err = os.Lstat(path)
+	assert.Nil(t, err)
+	assert.True(t, info.Mode()&os.ModeSymlink != 0, "the link was replaced")
+}
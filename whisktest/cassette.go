@@ -0,0 +1,328 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package whisktest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/apache/openwhisk-client-go/whisk"
+)
+
+// Permissions of a saved cassette file
+const CASSETTE_FILE_MODE os.FileMode = 0600
+
+// Mode of a Recorder
+type Mode int
+
+const (
+	ModeReplay Mode = iota // Serve the interactions of the cassette, without sending any request
+	ModeRecord             // Send the requests, and record the interactions into the cassette
+)
+
+// Cassette is the content of a cassette file: the recorded interactions, oldest first.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a recorded request and its response.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+type RecordedResponse struct {
+	StatusCode int         `json:"status"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+/*
+Recorder is an http.RoundTripper recording the interactions of a client into a cassette file, and serving them back
+offline:
+
+	recorder, err := whisktest.NewRecorder("testdata/list_actions.json", whisktest.ModeReplay, nil)
+	...
+	client, err := whisk.NewClient(recorder.HTTPClient(), config)
+
+In ModeRecord, the requests are sent through the given transport, and the cassette is written by Save.  The secrets
+of whisk.DefaultRedactionPolicy, such as the Authorization header and the access token of the API gateway, are
+scrubbed from the headers, URLs and JSON bodies of the recorded interactions; set Scrub to scrub the others.
+
+In ModeReplay, a request is served the response of the first interaction of the cassette not replayed yet with the
+same method, path and query parameters, in any order.  A request without such an interaction fails with an error
+naming the request and the cassette.
+*/
+type Recorder struct {
+	Path  string
+	Mode  Mode
+	Scrub func(interaction *Interaction) // Optional; called on each interaction before it is recorded
+
+	transport http.RoundTripper
+	mu        sync.Mutex
+	cassette  Cassette
+	replayed  []bool
+}
+
+// NewRecorder returns a recorder of the cassette file path.  In ModeRecord, the requests are sent through
+// transport, or http.DefaultTransport if it is nil.  In ModeReplay, the cassette file is read, and transport
+// is not used.
+func NewRecorder(path string, mode Mode, transport http.RoundTripper) (*Recorder, error) {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	r := &Recorder{Path: path, Mode: mode, transport: transport}
+
+	if mode == ModeReplay {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("whisktest: unable to read the cassette: %s", err)
+		}
+		if err = json.Unmarshal(data, &r.cassette); err != nil {
+			return nil, fmt.Errorf("whisktest: invalid cassette %s: %s", path, err)
+		}
+		r.replayed = make([]bool, len(r.cassette.Interactions))
+	}
+	return r, nil
+}
+
+// HTTPClient returns an HTTP client sending its requests through the recorder, to be given to whisk.NewClient.
+// The recorder is a whisk.RoundTripperWrapper: whisk.NewClient applies the TLS options of its configuration to
+// the transport given to NewRecorder.
+func (r *Recorder) HTTPClient() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Interactions returns the interactions recorded so far, or the ones of the cassette being replayed.
+func (r *Recorder) Interactions() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Interaction(nil), r.cassette.Interactions...)
+}
+
+// Save writes the recorded interactions to the cassette file.  The file is replaced atomically, and is only
+// readable and writable by its owner.  Save does nothing in ModeReplay.
+func (r *Recorder) Save() error {
+	if r.Mode != ModeRecord {
+		return nil
+	}
+	r.mu.Lock()
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(r.Path), "."+filepath.Base(r.Path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err = tmp.Chmod(CASSETTE_FILE_MODE); err == nil {
+		_, err = tmp.Write(append(data, '\n'))
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), r.Path)
+	}
+	return err
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	return r.roundTrip(req, r.transport)
+}
+
+// BaseTransport returns the transport the requests are sent through in ModeRecord.
+func (r *Recorder) BaseTransport() http.RoundTripper {
+	return r.transport
+}
+
+// WithBaseTransport returns an http.RoundTripper recording into r, which sends the requests through transport
+// instead.
+func (r *Recorder) WithBaseTransport(transport http.RoundTripper) http.RoundTripper {
+	return &recorderTransport{recorder: r, transport: transport}
+}
+
+func (r *Recorder) roundTrip(req *http.Request, transport http.RoundTripper) (*http.Response, error) {
+	if r.Mode == ModeReplay {
+		return r.replay(req)
+	}
+	return r.record(req, transport)
+}
+
+// recorderTransport records into a Recorder, through another transport than the recorder's one.
+type recorderTransport struct {
+	recorder  *Recorder
+	transport http.RoundTripper
+}
+
+func (rt *recorderTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return rt.recorder.roundTrip(req, rt.transport)
+}
+
+func (rt *recorderTransport) BaseTransport() http.RoundTripper {
+	return rt.transport
+}
+
+func (rt *recorderTransport) WithBaseTransport(transport http.RoundTripper) http.RoundTripper {
+	return rt.recorder.WithBaseTransport(transport)
+}
+
+func (r *Recorder) record(req *http.Request, transport http.RoundTripper) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		if reqBody, err = ioutil.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+		req = req.Clone(req.Context())
+		req.Body = ioutil.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+
+	interaction := Interaction{
+		Request: RecordedRequest{
+			Method: req.Method,
+			URL:    scrubURL(req.URL),
+			Header: scrubHeader(req.Header),
+			Body:   whisk.RedactBody(reqBody, nil),
+		},
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     scrubHeader(resp.Header),
+			Body:       whisk.RedactBody(respBody, nil),
+		},
+	}
+	if r.Scrub != nil {
+		r.Scrub(&interaction)
+	}
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	r.mu.Unlock()
+	return resp, nil
+}
+
+func (r *Recorder) replay(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	key := matchKey(req.Method, req.URL)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, interaction := range r.cassette.Interactions {
+		if r.replayed[i] {
+			continue
+		}
+		recordedURL, err := url.Parse(interaction.Request.URL)
+		if err != nil || matchKey(interaction.Request.Method, recordedURL) != key {
+			continue
+		}
+		r.replayed[i] = true
+
+		recorded := interaction.Response
+		header := recorded.Header.Clone()
+		if header == nil {
+			header = http.Header{}
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", recorded.StatusCode, http.StatusText(recorded.StatusCode)),
+			StatusCode:    recorded.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          ioutil.NopCloser(strings.NewReader(recorded.Body)),
+			ContentLength: int64(len(recorded.Body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("whisktest: no recorded interaction left for %s in the cassette %s", key, r.Path)
+}
+
+// matchKey returns the method, path and normalized query of a request: the query parameters are sorted, and the
+// scrubbed ones match any value.
+func matchKey(method string, u *url.URL) string {
+	query := scrubQuery(u.Query())
+	for name := range query {
+		sort.Strings(query[name])
+	}
+	key := strings.ToUpper(method) + " " + u.EscapedPath()
+	if len(query) > 0 {
+		key += "?" + query.Encode()
+	}
+	return key
+}
+
+func scrubHeader(header http.Header) http.Header {
+	scrubbed := header.Clone()
+	for _, name := range whisk.DefaultRedactionPolicy.Headers {
+		if _, ok := scrubbed[http.CanonicalHeaderKey(name)]; ok {
+			scrubbed.Set(name, whisk.REDACTED)
+		}
+	}
+	return scrubbed
+}
+
+func scrubURL(u *url.URL) string {
+	scrubbed := *u
+	if len(u.RawQuery) > 0 {
+		scrubbed.RawQuery = scrubQuery(u.Query()).Encode()
+	}
+	return scrubbed.String()
+}
+
+func scrubQuery(query url.Values) url.Values {
+	for _, name := range whisk.DefaultRedactionPolicy.QueryParams {
+		if _, ok := query[name]; ok {
+			query.Set(name, whisk.REDACTED)
+		}
+	}
+	return query
+}
@@ -0,0 +1,157 @@
+// +build unit
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package whisktest
+
+import (
+	"github.com/apache/openwhisk-client-go/whisk"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	dir, err := ioutil.TempDir("", "whisktest")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "cassette.json")
+
+	// Record the interactions with the fake server
+	server := NewServer()
+	recorder, err := NewRecorder(path, ModeRecord, nil)
+	assert.Nil(t, err)
+	client, err := whisk.NewClient(recorder.HTTPClient(), server.Config())
+	assert.Nil(t, err)
+
+	code := "function main() {}"
+	_, _, err = client.Actions.Insert(&whisk.Action{Name: "hello", Exec: &whisk.Exec{Kind: "nodejs:default", Code: &code}}, false)
+	assert.Nil(t, err)
+	_, _, err = client.Actions.List("", &whisk.ActionListOptions{Limit: 10, Skip: 0})
+	assert.Nil(t, err)
+	server.Close()
+	assert.Nil(t, recorder.Save())
+
+	data, err := ioutil.ReadFile(path)
+	assert.Nil(t, err)
+	assert.NotContains(t, string(data), "Basic ")
+	assert.Equal(t, 2, len(recorder.Interactions()))
+	assert.Equal(t, whisk.REDACTED, recorder.Interactions()[0].Request.Header.Get("Authorization"))
+
+	// Replay them offline
+	replayer, err := NewRecorder(path, ModeReplay, nil)
+	assert.Nil(t, err)
+	client, err = whisk.NewClient(replayer.HTTPClient(), server.Config())
+	assert.Nil(t, err)
+
+	actions, _, err := client.Actions.List("", &whisk.ActionListOptions{Limit: 10, Skip: 0})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(actions))
+	assert.Equal(t, "hello", actions[0].Name)
+
+	created, _, err := client.Actions.Insert(&whisk.Action{Name: "hello", Exec: &whisk.Exec{Kind: "nodejs:default", Code: &code}}, false)
+	assert.Nil(t, err)
+	assert.Equal(t, "0.0.1", created.Version)
+
+	// Each interaction is replayed once
+	_, _, err = client.Actions.Insert(&whisk.Action{Name: "hello", Exec: &whisk.Exec{Kind: "nodejs:default", Code: &code}}, false)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "no recorded interaction left for PUT /api/v1/namespaces/guest/actions/hello")
+	assert.Contains(t, err.Error(), path)
+}
+
+func TestRecordWithTLSOptions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "whisktest")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	// The self-signed certificate of the server is only accepted with Insecure
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"name": "hello", "namespace": "guest"}]`))
+	}))
+	defer server.Close()
+
+	recorder, err := NewRecorder(filepath.Join(dir, "cassette.json"), ModeRecord, nil)
+	assert.Nil(t, err)
+	client, err := whisk.NewClient(recorder.HTTPClient(), &whisk.Config{Host: server.URL, AuthToken: DefaultAuthToken, Insecure: true})
+	assert.Nil(t, err)
+
+	actions, _, err := client.Actions.List("", &whisk.ActionListOptions{Limit: 10, Skip: 0})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(actions))
+	assert.Equal(t, 1, len(recorder.Interactions()))
+	assert.True(t, recorder.BaseTransport() == http.DefaultTransport)
+}
+
+func TestReplayMatching(t *testing.T) {
+	u, _ := url.Parse("https://example.com/api/v1/web/guest/apimgmt/getApi.http?spaceguid=s&accesstoken=secret&b=2&b=1")
+	recorded, _ := url.Parse(scrubURL(u))
+	assert.Equal(t, "GET /api/v1/web/guest/apimgmt/getApi.http?accesstoken=%2A%2A%2A%2A%2A%2A&b=1&b=2&spaceguid=s",
+		matchKey("get", u))
+	assert.Equal(t, matchKey("GET", u), matchKey("GET", recorded))
+
+	other, _ := url.Parse("https://example.com/api/v1/web/guest/apimgmt/getApi.http?spaceguid=t&accesstoken=secret&b=2&b=1")
+	assert.NotEqual(t, matchKey("GET", u), matchKey("GET", other))
+	assert.NotEqual(t, matchKey("GET", u), matchKey("DELETE", u))
+
+	_, err := NewRecorder("does-not-exist.json", ModeReplay, nil)
+	assert.NotNil(t, err)
+}
+
+func TestRecordScrubsBodies(t *testing.T) {
+	dir, err := ioutil.TempDir("", "whisktest")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "cassette.json")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"accesstoken":"t0k3n-s3cr3t","name":"api"}`))
+	}))
+	defer server.Close()
+
+	recorder, err := NewRecorder(path, ModeRecord, nil)
+	assert.Nil(t, err)
+	body := `{"apidoc":{"action":{"authkey":"4uth-s3cr3t","name":"hello"}}}`
+	resp, err := recorder.HTTPClient().Post(server.URL+"/api", "application/json", strings.NewReader(body))
+	assert.Nil(t, err)
+	data, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Nil(t, err)
+	assert.Contains(t, string(data), "t0k3n-s3cr3t", "the client gets the response unchanged")
+	assert.Nil(t, recorder.Save())
+
+	data, err = ioutil.ReadFile(path)
+	assert.Nil(t, err)
+	assert.NotContains(t, string(data), "4uth-s3cr3t")
+	assert.NotContains(t, string(data), "t0k3n-s3cr3t")
+	interaction := recorder.Interactions()[0]
+	assert.Equal(t, `{"apidoc":{"action":{"authkey":"******","name":"hello"}}}`, interaction.Request.Body)
+	assert.Equal(t, `{"accesstoken":"******","name":"api"}`, interaction.Response.Body)
+
+	info, err := os.Stat(path)
+	assert.Nil(t, err)
+	assert.Equal(t, CASSETTE_FILE_MODE, info.Mode().Perm())
+}
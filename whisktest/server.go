@@ -0,0 +1,551 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package whisktest provides an in-process fake of the OpenWhisk controller API, for testing code that uses
+// the whisk client without a running OpenWhisk deployment.
+package whisktest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/apache/openwhisk-client-go/whisk"
+)
+
+const (
+	DefaultNamespace = "guest"
+	DefaultAuthToken = "23bc46b1-71f6-4ed5-8c54-816aa4f8c502:123zO3xZCLrMN6v2BKK1dXYFpXlPkccOFqm12CdAsMgRU4VrNZ9lyGVCGuMDGIwP"
+
+	defaultListLimit = 30
+	maxListLimit     = 200
+)
+
+// ActionFunc implements an action of the fake server.  The returned error, if any, is reported as the
+// activation's application error.
+type ActionFunc func(params map[string]interface{}) (map[string]interface{}, error)
+
+// Server is a fake OpenWhisk controller serving the actions, triggers, rules, packages and activations
+// collections of any namespace.  The "_" namespace stands for DefaultNamespace.
+//
+// Entities are stored as they are sent, with their name, namespace and version set by the server.  Invoking
+// an action records an activation whose result is computed by the ActionFunc registered with HandleAction;
+// actions without one return their parameters.  Firing a trigger records an activation of the trigger only,
+// rules are not evaluated.
+type Server struct {
+	URL string // Base URL of the server, to be used as the client's Host
+
+	server      *httptest.Server
+	mu          sync.Mutex
+	entities    map[string]map[string]entity // namespace -> collection/name -> entity
+	activations []map[string]interface{}     // Oldest first
+	handlers    map[string]ActionFunc        // namespace/name -> implementation
+	counter     int64
+}
+
+type entity struct {
+	doc     map[string]interface{}
+	updated int64
+}
+
+// NewServer starts a fake controller.  The caller should call Close when finished, to shut it down.
+func NewServer() *Server {
+	s := &Server{
+		entities: make(map[string]map[string]entity),
+		handlers: make(map[string]ActionFunc),
+	}
+	s.server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.server.URL
+	return s
+}
+
+// Close shuts down the server and blocks until all outstanding requests on this server have completed.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// Config returns a whisk client configuration pointing at the server, for DefaultNamespace.
+func (s *Server) Config() *whisk.Config {
+	return &whisk.Config{
+		Host:      s.URL,
+		Namespace: DefaultNamespace,
+		AuthToken: DefaultAuthToken,
+	}
+}
+
+// Client returns a whisk client pointing at the server, for DefaultNamespace.
+func (s *Server) Client() (*whisk.Client, error) {
+	return whisk.NewClient(s.server.Client(), s.Config())
+}
+
+// HandleAction registers the implementation of the action `name` ("action" or "package/action") of the given
+// namespace.  The action itself still has to be created before it can be invoked.
+func (s *Server) HandleAction(namespace string, name string, fn ActionFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[namespace+"/"+name] = fn
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if len(r.Header.Get("Authorization")) == 0 {
+		s.writeError(w, http.StatusUnauthorized, "The resource requires authentication, which was not supplied with the request")
+		return
+	}
+
+	path := strings.Trim(r.URL.Path, "/")
+	if path == "api/v1/namespaces" {
+		s.listNamespaces(w)
+		return
+	}
+
+	// api/v1/namespaces/{namespace}/{collection}[/{name}]
+	parts := strings.SplitN(path, "/", 6)
+	if len(parts) < 5 || parts[0] != "api" || parts[1] != "v1" || parts[2] != "namespaces" {
+		s.writeError(w, http.StatusNotFound, "The requested resource does not exist.")
+		return
+	}
+	namespace, collection, name := parts[3], parts[4], ""
+	if namespace == "_" {
+		namespace = DefaultNamespace
+	}
+	if len(parts) == 6 {
+		name = strings.Trim(parts[5], "/")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch collection {
+	case "actions", "triggers", "rules", "packages":
+		s.serveEntity(w, r, namespace, collection, name)
+	case "activations":
+		s.serveActivation(w, r, namespace, name)
+	default:
+		s.writeError(w, http.StatusNotFound, "The requested resource does not exist.")
+	}
+}
+
+func (s *Server) serveEntity(w http.ResponseWriter, r *http.Request, namespace string, collection string, name string) {
+	if len(name) == 0 || (collection == "actions" && strings.HasSuffix(r.URL.Path, "/")) {
+		if r.Method != "GET" {
+			s.writeError(w, http.StatusMethodNotAllowed, "HTTP method not allowed, supported methods: GET")
+			return
+		}
+		s.listEntities(w, r, namespace, collection, name)
+		return
+	}
+	if strings.Count(name, "/") > 1 || (collection != "actions" && strings.Contains(name, "/")) {
+		s.writeError(w, http.StatusBadRequest, fmt.Sprintf("The entity name '%s' is not valid.", name))
+		return
+	}
+
+	key := collection + "/" + name
+	existing, exists := s.namespace(namespace)[key]
+
+	switch r.Method {
+	case "GET":
+		if !exists {
+			s.writeError(w, http.StatusNotFound, "The requested resource does not exist.")
+			return
+		}
+		s.writeJSON(w, http.StatusOK, existing.doc)
+
+	case "PUT":
+		if exists && r.URL.Query().Get("overwrite") != "true" {
+			s.writeError(w, http.StatusConflict, "resource already exists")
+			return
+		}
+		if pkg := packageOf(name); len(pkg) > 0 {
+			if _, ok := s.namespace(namespace)["packages/"+pkg]; !ok {
+				s.writeError(w, http.StatusNotFound, "The requested resource does not exist.")
+				return
+			}
+		}
+		doc := make(map[string]interface{})
+		if err := json.NewDecoder(r.Body).Decode(&doc); err != nil {
+			s.writeError(w, http.StatusBadRequest, fmt.Sprintf("The request content was malformed:\n%s", err))
+			return
+		}
+		s.putEntity(namespace, collection, name, doc, existing, exists)
+		s.writeJSON(w, http.StatusOK, doc)
+
+	case "DELETE":
+		if !exists {
+			s.writeError(w, http.StatusNotFound, "The requested resource does not exist.")
+			return
+		}
+		if collection == "packages" && s.packageHasActions(namespace, name) {
+			s.writeError(w, http.StatusConflict, "Package not empty (contains actions).")
+			return
+		}
+		delete(s.namespace(namespace), key)
+		s.writeJSON(w, http.StatusOK, existing.doc)
+
+	case "POST":
+		if !exists {
+			s.writeError(w, http.StatusNotFound, "The requested resource does not exist.")
+			return
+		}
+		switch collection {
+		case "actions":
+			s.invoke(w, r, namespace, name)
+		case "triggers":
+			s.fire(w, namespace, name)
+		case "rules":
+			s.setRuleState(w, r, existing)
+		default:
+			s.writeError(w, http.StatusMethodNotAllowed, "HTTP method not allowed, supported methods: GET, PUT, DELETE")
+		}
+
+	default:
+		s.writeError(w, http.StatusMethodNotAllowed, "HTTP method not allowed, supported methods: GET, PUT, POST, DELETE")
+	}
+}
+
+func (s *Server) putEntity(namespace string, collection string, name string, doc map[string]interface{}, existing entity, exists bool) {
+	doc["name"] = baseName(name)
+	doc["namespace"] = namespace
+	if pkg := packageOf(name); len(pkg) > 0 {
+		doc["namespace"] = namespace + "/" + pkg
+	}
+
+	version := "0.0.1"
+	if exists {
+		version = nextVersion(existing.doc["version"])
+	}
+	doc["version"] = version
+	if _, ok := doc["publish"]; !ok {
+		doc["publish"] = false
+	}
+	if _, ok := doc["annotations"]; !ok {
+		doc["annotations"] = []interface{}{}
+	}
+	if collection == "rules" {
+		doc["status"] = "active"
+		doc["trigger"] = s.qualify(namespace, doc["trigger"])
+		doc["action"] = s.qualify(namespace, doc["action"])
+	}
+
+	updated := s.now()
+	doc["updated"] = updated
+	s.namespace(namespace)[collection+"/"+name] = entity{doc: doc, updated: updated}
+}
+
+func (s *Server) listEntities(w http.ResponseWriter, r *http.Request, namespace string, collection string, pkg string) {
+	limit, skip, ok := s.listLimits(w, r)
+	if !ok {
+		return
+	}
+
+	var entities []entity
+	prefix := collection + "/"
+	for key, e := range s.namespace(namespace) {
+		if strings.HasPrefix(key, prefix) && packageOf(strings.TrimPrefix(key, prefix)) == pkg {
+			entities = append(entities, e)
+		}
+	}
+	// Most recently updated first, like the controller
+	sort.Slice(entities, func(i, j int) bool {
+		return entities[i].updated > entities[j].updated
+	})
+
+	docs := make([]map[string]interface{}, len(entities))
+	for i, e := range entities {
+		docs[i] = e.doc
+	}
+
+	s.writeJSON(w, http.StatusOK, page(docs, limit, skip))
+}
+
+func (s *Server) listNamespaces(w http.ResponseWriter) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	namespaces := []string{DefaultNamespace}
+	for namespace := range s.entities {
+		if namespace != DefaultNamespace {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	sort.Strings(namespaces[1:])
+	s.writeJSON(w, http.StatusOK, namespaces)
+}
+
+func (s *Server) invoke(w http.ResponseWriter, r *http.Request, namespace string, name string) {
+	var params map[string]interface{}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			s.writeError(w, http.StatusBadRequest, fmt.Sprintf("The request content was malformed:\n%s", err))
+			return
+		}
+	}
+	if params == nil {
+		params = make(map[string]interface{})
+	}
+
+	start := s.now()
+	result, status, success := params, "success", true
+	if fn, ok := s.handlers[namespace+"/"+name]; ok {
+		var err error
+		if result, err = fn(params); err != nil {
+			result = map[string]interface{}{"error": err.Error()}
+			status, success = "application error", false
+		}
+	}
+
+	activation := s.record(namespace, name, start, map[string]interface{}{
+		"status":     status,
+		"statusCode": whisk.GetStatusCodeForMessage(status),
+		"success":    success,
+		"result":     result,
+	})
+
+	query := r.URL.Query()
+	code := http.StatusOK
+	if !success {
+		code = http.StatusBadGateway
+	}
+	switch {
+	case query.Get("blocking") != "true":
+		s.writeJSON(w, http.StatusAccepted, map[string]interface{}{"activationId": activation["activationId"]})
+	case query.Get("result") == "true":
+		s.writeJSON(w, code, result)
+	default:
+		s.writeJSON(w, code, activation)
+	}
+}
+
+func (s *Server) fire(w http.ResponseWriter, namespace string, name string) {
+	activation := s.record(namespace, name, s.now(), map[string]interface{}{
+		"status":     "success",
+		"statusCode": 0,
+		"success":    true,
+	})
+	s.writeJSON(w, http.StatusAccepted, map[string]interface{}{"activationId": activation["activationId"]})
+}
+
+func (s *Server) setRuleState(w http.ResponseWriter, r *http.Request, rule entity) {
+	state := struct {
+		Status string `json:"status"`
+	}{}
+	if err := json.NewDecoder(r.Body).Decode(&state); err != nil || (state.Status != "active" && state.Status != "inactive") {
+		s.writeError(w, http.StatusBadRequest, "The request content was malformed:\nstatus must be 'active' or 'inactive'")
+		return
+	}
+	rule.doc["status"] = state.Status
+	s.writeJSON(w, http.StatusOK, rule.doc)
+}
+
+func (s *Server) record(namespace string, name string, start int64, response map[string]interface{}) map[string]interface{} {
+	end := s.now()
+	activation := map[string]interface{}{
+		"namespace":    namespace,
+		"name":         baseName(name),
+		"version":      "0.0.1",
+		"subject":      namespace,
+		"activationId": fmt.Sprintf("%032x", s.counter),
+		"start":        start,
+		"end":          end,
+		"duration":     end - start,
+		"response":     response,
+		"logs":         []string{},
+		"annotations":  []interface{}{},
+	}
+	s.activations = append(s.activations, activation)
+	return activation
+}
+
+func (s *Server) serveActivation(w http.ResponseWriter, r *http.Request, namespace string, path string) {
+	if r.Method != "GET" {
+		s.writeError(w, http.StatusMethodNotAllowed, "HTTP method not allowed, supported methods: GET")
+		return
+	}
+	if len(path) == 0 {
+		s.listActivations(w, r, namespace)
+		return
+	}
+
+	parts := strings.SplitN(path, "/", 2)
+	var activation map[string]interface{}
+	for _, a := range s.activations {
+		if a["activationId"] == parts[0] && a["namespace"] == namespace {
+			activation = a
+		}
+	}
+	if activation == nil {
+		s.writeError(w, http.StatusNotFound, "The requested resource does not exist.")
+		return
+	}
+
+	switch {
+	case len(parts) == 1:
+		s.writeJSON(w, http.StatusOK, activation)
+	case parts[1] == "logs":
+		s.writeJSON(w, http.StatusOK, map[string]interface{}{"logs": activation["logs"]})
+	case parts[1] == "result":
+		s.writeJSON(w, http.StatusOK, activation["response"])
+	default:
+		s.writeError(w, http.StatusNotFound, "The requested resource does not exist.")
+	}
+}
+
+func (s *Server) listActivations(w http.ResponseWriter, r *http.Request, namespace string) {
+	limit, skip, ok := s.listLimits(w, r)
+	if !ok {
+		return
+	}
+	query := r.URL.Query()
+	name := query.Get("name")
+	since, _ := strconv.ParseInt(query.Get("since"), 10, 64)
+	upto, _ := strconv.ParseInt(query.Get("upto"), 10, 64)
+
+	var docs []map[string]interface{}
+	for i := len(s.activations) - 1; i >= 0; i-- {
+		a := s.activations[i]
+		start := a["start"].(int64)
+		if a["namespace"] != namespace || (len(name) > 0 && a["name"] != baseName(name)) ||
+			(since > 0 && start < since) || (upto > 0 && start > upto) {
+			continue
+		}
+		docs = append(docs, a)
+	}
+
+	s.writeJSON(w, http.StatusOK, page(docs, limit, skip))
+}
+
+// listLimits returns the limit and skip query parameters, or writes an error response when they are invalid.
+func (s *Server) listLimits(w http.ResponseWriter, r *http.Request) (int, int, bool) {
+	query := r.URL.Query()
+	limit, skip := defaultListLimit, 0
+	var err error
+
+	if value := query.Get("limit"); len(value) > 0 {
+		if limit, err = strconv.Atoi(value); err != nil || limit < 0 {
+			s.writeError(w, http.StatusBadRequest, fmt.Sprintf("The value '%s' is not a valid limit.", value))
+			return 0, 0, false
+		}
+		if limit > maxListLimit {
+			s.writeError(w, http.StatusBadRequest, fmt.Sprintf("The value %d exceeds the allowed threshold %d.", limit, maxListLimit))
+			return 0, 0, false
+		}
+		if limit == 0 {
+			limit = defaultListLimit
+		}
+	}
+	if value := query.Get("skip"); len(value) > 0 {
+		if skip, err = strconv.Atoi(value); err != nil || skip < 0 {
+			s.writeError(w, http.StatusBadRequest, fmt.Sprintf("The value '%s' is not a valid skip.", value))
+			return 0, 0, false
+		}
+	}
+
+	return limit, skip, true
+}
+
+func (s *Server) namespace(namespace string) map[string]entity {
+	entities, ok := s.entities[namespace]
+	if !ok {
+		entities = make(map[string]entity)
+		s.entities[namespace] = entities
+	}
+	return entities
+}
+
+func (s *Server) packageHasActions(namespace string, pkg string) bool {
+	for key := range s.namespace(namespace) {
+		if strings.HasPrefix(key, "actions/"+pkg+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// qualify turns a rule's trigger or action name into the {name, path} object the controller returns
+func (s *Server) qualify(namespace string, ref interface{}) interface{} {
+	name, ok := ref.(string)
+	if !ok {
+		return ref
+	}
+	name = strings.TrimPrefix(name, "/")
+	path := namespace
+	if parts := strings.Split(name, "/"); len(parts) > 1 {
+		path, name = strings.Join(parts[:len(parts)-1], "/"), parts[len(parts)-1]
+		if path == "_" {
+			path = namespace
+		}
+	}
+	return map[string]interface{}{"name": name, "path": path}
+}
+
+// now returns the current time in milliseconds, strictly increasing so that ordering by time is deterministic
+func (s *Server) now() int64 {
+	now := time.Now().UnixNano() / int64(time.Millisecond)
+	if now <= s.counter {
+		now = s.counter + 1
+	}
+	s.counter = now
+	return now
+}
+
+func (s *Server) writeError(w http.ResponseWriter, status int, msg string) {
+	s.writeJSON(w, status, map[string]interface{}{
+		"error": msg,
+		"code":  strconv.FormatInt(time.Now().UnixNano(), 16),
+	})
+}
+
+func (s *Server) writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func page(docs []map[string]interface{}, limit int, skip int) []map[string]interface{} {
+	if skip >= len(docs) {
+		return []map[string]interface{}{}
+	}
+	docs = docs[skip:]
+	if limit < len(docs) {
+		docs = docs[:limit]
+	}
+	return docs
+}
+
+func packageOf(name string) string {
+	if i := strings.Index(name, "/"); i >= 0 {
+		return name[:i]
+	}
+	return ""
+}
+
+func baseName(name string) string {
+	return name[strings.LastIndex(name, "/")+1:]
+}
+
+func nextVersion(version interface{}) string {
+	var major, minor, patch int
+	if v, ok := version.(string); ok {
+		fmt.Sscanf(v, "%d.%d.%d", &major, &minor, &patch)
+	}
+	return fmt.Sprintf("%d.%d.%d", major, minor, patch+1)
+}
@@ -0,0 +1,193 @@
+// +build unit
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package whisktest
+
+import (
+	"errors"
+	"fmt"
+	"github.com/apache/openwhisk-client-go/whisk"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func newTestClient(t *testing.T) (*Server, *whisk.Client) {
+	server := NewServer()
+	client, err := server.Client()
+	if err != nil {
+		server.Close()
+		t.Fatalf("NewClient() failed: %s", err)
+	}
+	return server, client
+}
+
+func exitCode(err error) int {
+	if whiskErr, ok := err.(*whisk.WskError); ok {
+		return whiskErr.ExitCode
+	}
+	return 0
+}
+
+func TestActionCRUD(t *testing.T) {
+	server, client := newTestClient(t)
+	defer server.Close()
+
+	code := "function main() {}"
+	action := &whisk.Action{Name: "hello", Exec: &whisk.Exec{Kind: "nodejs:default", Code: &code}}
+	created, _, err := client.Actions.Insert(action, false)
+	assert.Nil(t, err)
+	assert.Equal(t, "hello", created.Name)
+	assert.Equal(t, DefaultNamespace, created.Namespace)
+	assert.Equal(t, "0.0.1", created.Version)
+
+	_, resp, err := client.Actions.Insert(action, false)
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+	updated, _, err := client.Actions.Insert(action, true)
+	assert.Nil(t, err)
+	assert.Equal(t, "0.0.2", updated.Version)
+
+	fetched, _, err := client.Actions.Get("hello", true)
+	assert.Nil(t, err)
+	assert.Equal(t, code, *fetched.Exec.Code)
+
+	_, err = client.Actions.Delete("hello")
+	assert.Nil(t, err)
+	_, _, err = client.Actions.Get("hello", false)
+	assert.Equal(t, whisk.EXIT_CODE_NOT_FOUND, exitCode(err))
+	_, err = client.Actions.Delete("hello")
+	assert.Equal(t, whisk.EXIT_CODE_NOT_FOUND, exitCode(err))
+}
+
+func TestListLimitAndSkip(t *testing.T) {
+	server, client := newTestClient(t)
+	defer server.Close()
+
+	for i := 0; i < 5; i++ {
+		_, _, err := client.Triggers.Insert(&whisk.Trigger{Name: fmt.Sprintf("trigger%d", i)}, false)
+		assert.Nil(t, err)
+	}
+
+	triggers, _, err := client.Triggers.List(&whisk.TriggerListOptions{Limit: 2, Skip: 1})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(triggers))
+	assert.Equal(t, "trigger3", triggers[0].Name)
+	assert.Equal(t, "trigger2", triggers[1].Name)
+
+	pager := client.Triggers.(whisk.TriggerServiceContextInterface).ListAll(nil)
+	pager.SetPageSize(2)
+	all, err := pager.All()
+	assert.Nil(t, err)
+	assert.Equal(t, 5, len(all))
+
+	_, resp, err := client.Triggers.List(&whisk.TriggerListOptions{Limit: 201})
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestPackageActions(t *testing.T) {
+	server, client := newTestClient(t)
+	defer server.Close()
+
+	action := &whisk.Action{Name: "utils/echo", Exec: &whisk.Exec{Kind: "nodejs:default"}}
+	_, resp, err := client.Actions.Insert(action, false)
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	_, _, err = client.Packages.Insert(&whisk.Package{Name: "utils"}, false)
+	assert.Nil(t, err)
+	created, _, err := client.Actions.Insert(action, false)
+	assert.Nil(t, err)
+	assert.Equal(t, "echo", created.Name)
+	assert.Equal(t, DefaultNamespace+"/utils", created.Namespace)
+
+	actions, _, err := client.Actions.List("utils", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(actions))
+	actions, _, err = client.Actions.List("", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(actions))
+
+	_, err = client.Packages.Delete("utils")
+	assert.NotNil(t, err)
+}
+
+func TestInvokeAndActivations(t *testing.T) {
+	server, client := newTestClient(t)
+	defer server.Close()
+
+	server.HandleAction(DefaultNamespace, "greet", func(params map[string]interface{}) (map[string]interface{}, error) {
+		if params["name"] == nil {
+			return nil, errors.New("missing name")
+		}
+		return map[string]interface{}{"greeting": fmt.Sprintf("Hello %s", params["name"])}, nil
+	})
+	_, _, err := client.Actions.Insert(&whisk.Action{Name: "greet", Exec: &whisk.Exec{Kind: "nodejs:default"}}, false)
+	assert.Nil(t, err)
+
+	result, _, err := client.Actions.Invoke("greet", map[string]interface{}{"name": "world"}, true, true)
+	assert.Nil(t, err)
+	assert.Equal(t, "Hello world", result["greeting"])
+
+	_, _, err = client.Actions.Invoke("greet", nil, true, true)
+	assert.NotNil(t, err)
+	assert.True(t, err.(*whisk.WskError).ApplicationError)
+
+	accepted, resp, err := client.Actions.Invoke("greet", map[string]interface{}{"name": "async"}, false, false)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+	id := accepted["activationId"].(string)
+
+	activation, _, err := client.Activations.Get(id)
+	assert.Nil(t, err)
+	assert.Equal(t, "greet", activation.Name)
+	assert.Equal(t, "Hello async", (*activation.Response.Result)["greeting"])
+
+	activations, _, err := client.Activations.List(&whisk.ActivationListOptions{Name: "greet", Limit: 2})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(activations))
+	assert.Equal(t, id, activations[0].ActivationID)
+
+	_, _, err = client.Actions.Invoke("missing", nil, true, true)
+	assert.Equal(t, whisk.EXIT_CODE_NOT_FOUND, exitCode(err))
+}
+
+func TestTriggersAndRules(t *testing.T) {
+	server, client := newTestClient(t)
+	defer server.Close()
+
+	_, _, err := client.Triggers.Insert(&whisk.Trigger{Name: "tick"}, false)
+	assert.Nil(t, err)
+	fired, _, err := client.Triggers.Fire("tick", nil)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, fired.ActivationId)
+
+	rule, _, err := client.Rules.Insert(&whisk.Rule{Name: "on-tick", Trigger: "tick", Action: "hello"}, false)
+	assert.Nil(t, err)
+	assert.Equal(t, "active", rule.Status)
+
+	rule, _, err = client.Rules.SetState("on-tick", "inactive")
+	assert.Nil(t, err)
+	assert.Equal(t, "inactive", rule.Status)
+	rule, _, err = client.Rules.Get("on-tick")
+	assert.Nil(t, err)
+	assert.Equal(t, "inactive", rule.Status)
+}
@@ -20,6 +20,7 @@ package wski18n
 import (
 	"path/filepath"
 	"strings"
+	"sync/atomic"
 
 	goi18n "github.com/nicksnyder/go-i18n/i18n"
 )
@@ -51,9 +52,16 @@ func SetResourcePath(path string) {
 	resourcePath = path
 }
 
-var T goi18n.TranslateFunc
+// T translates with the current locale.  It is safe for concurrent use, including with InitWithLocale.
+var T goi18n.TranslateFunc = translate
 var curLocale string
 
+var translateFunc atomic.Value // goi18n.TranslateFunc of the current locale
+
+func translate(translationID string, args ...interface{}) string {
+	return translateFunc.Load().(goi18n.TranslateFunc)(translationID, args...)
+}
+
 func init() {
 	curLocale = Init(new(JibberJabberDetector))
 }
@@ -89,7 +97,7 @@ func InitWithLocale(locale string) {
 	if err != nil {
 		panic(err)
 	}
-	T = goi18n.MustTfunc(locale)
+	translateFunc.Store(goi18n.MustTfunc(locale))
 }
 
 func loadFromAsset(locale string) (err error) {
@@ -114,7 +114,7 @@ func wski18nResourcesDe_deAllJson() (*asset, error) {
 	return a, nil
 }
 
-var _wski18nResourcesEn_usAllJson = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xcc\x58\x5f\x6f\xdb\x36\x10\x7f\xf7\xa7\x38\xf8\xc5\x19\xe0\x0a\x7b\xd9\xc3\xba\xa7\xa0\x33\xe6\xa0\x5d\x63\xac\xce\x3a\x60\x19\x06\x46\x3c\x5b\x87\xc8\xa4\x7a\xa4\x9c\xb9\x86\xbe\xfb\x40\xca\x72\xd2\xc4\xb4\xfe\x58\x49\xf3\x64\x81\xe6\xfd\xee\xc7\xbb\xe3\xfd\xe1\xdf\x03\x80\xed\x00\x00\x60\x48\x72\xf8\x16\x86\x57\x4a\xdc\xa4\x08\x56\x83\x90\x12\x58\xe7\x16\x41\x67\x96\xb4\x32\x30\xda\x6e\xa3\xdd\x77\x51\x8c\x86\xe3\x52\xce\xb2\x50\x26\x15\x6e\xb9\x06\xe0\x2d\x3c\x04\x18\x0e\x00\x8a\x71\x58\x7f\xcc\x28\x2c\xc2\x74\x3e\x9f\x01\xe3\x97\x1c\x8d\x85\x85\x66\x98\x5d\xcd\x3d\x13\x0f\x5d\x14\x23\x8f\x8a\xcc\x45\x51\xcb\xa8\x03\x64\x47\x92\xbf\x4d\x7a\x27\x79\x04\xb2\x23\xc9\x5f\x27\x1f\x26\xf3\x49\xdf\x3c\x8f\xa3\x76\x75\xfa\xe5\xa7\xfe\xbd\x7e\x04\xb3\x86\xa6\xc8\x32\x54\x32\x70\x31\xdc\x86\xab\x3f\x3e\xec\x62\xbf\x23\xe9\xd3\x35\x34\xb3\x74\x65\x10\x07\xe7\x80\x72\x4e\x3b\x59\xb7\x16\xe7\x20\x9d\x0b\xb5\x16\x29\xc9\xae\x2c\x1a\x8b\x1f\x54\x3e\x61\xd6\x0c\xa8\x62\x2d\x49\x2d\xf7\x20\x37\x5a\x6e\x6a\x35\x37\x93\x3d\xa2\x96\x14\x59\x12\x29\x7d\x7d\x20\xde\x50\x6b\x8d\x68\x5d\xe8\x4a\x09\x36\xd9\x5d\x07\x91\xdb\x04\x95\xa5\xd8\xab\x80\x04\x85\x44\x6e\x13\xa5\x6d\xc0\x0e\x12\x3b\xcf\x6d\xa2\x99\xbe\x96\x32\xb7\xb8\x01\x32\xa0\xb4\x85\x58\xab\x05\x2d\x73\x46\x09\x67\x6f\xde\x38\x6c\xf7\x8f\x3b\x2e\x31\xca\x1f\x02\xd4\x3a\xc3\x1d\x26\xa7\xe0\x7c\x76\x01\x89\x36\x16\x56\xb9\xf3\x2f\x42\xc6\x7a\x4d\x12\x65\x74\xad\x42\x1c\x6a\xa4\x1a\x38\xe8\xe5\xeb\xee\x3b\xbd\x5a\x09\x25\x61\x21\x28\x45\x09\x32\x2f\xa1\x54\xe9\x5a\xb7\x9a\x33\x06\x54\x37\x93\x3d\xa8\xf6\xa3\x06\x52\x16\x79\x21\xe2\x7b\x23\xfd\x02\x4a\x57\xe9\xda\x64\x5a\x19\xf4\x17\x0b\xf0\xbf\x0c\x63\x8b\x32\x40\xa3\x1b\x56\x3b\x6b\x78\x05\x4a\xa4\x5d\x2d\xf2\x44\xfe\xa0\xfa\x79\x82\xb0\xd0\x69\xaa\xef\xdc\x15\x17\x59\x96\x56\x97\x0a\x7d\x06\xb8\x13\x2e\x74\x63\xa4\x35\xca\xda\xdb\xda\x11\xec\xf5\xe5\xeb\x57\x9b\xc3\xee\xb1\xdc\x81\x32\xc1\xa6\x2c\xca\x6b\x64\x43\x5a\xb5\xab\xa7\x0d\x20\x4e\xec\x49\xbb\x96\xf8\xe6\x80\xdd\x09\xf6\xc3\xaa\x39\x95\x9b\x9c\xd2\x6f\xe2\xb1\x05\x81\x63\xb2\xcd\x2c\xe0\x0c\xf8\x84\xff\x69\x5d\x6e\x1b\xc8\x66\x24\xdd\x70\xd4\x33\xc9\x36\x90\xcd\x48\xee\x86\x8e\x9e\x79\xb6\x44\x6d\x68\x4f\x37\x76\xf4\x6d\xd0\x36\x98\x81\xcc\xbe\x2b\x4c\xbe\x28\x44\x50\xa5\x6a\x63\xc5\xbe\x91\xf0\x80\x7e\xa1\x28\x46\x11\xfc\xe9\x37\x54\x4d\x8a\x60\x84\xeb\xa1\x88\x2d\xad\xf1\x7a\x08\xae\xfa\x5d\x0f\x49\x55\x0b\x51\xb0\x24\x3c\xb7\xde\x1a\xaf\x94\xb9\xb6\xaa\x44\x1d\x5c\x50\x0b\x50\x47\x80\x75\x8c\xc6\x78\x84\x2f\x39\xf2\x26\xd0\xf7\xb5\xa1\xd4\x1e\xf2\x20\xc9\xed\x36\x5a\x99\x65\x51\xc0\x59\xac\x25\xba\xcd\xee\xb7\x28\x42\xdd\x77\x78\x7f\xb0\xcd\x89\xb5\x52\x18\x7b\x2f\x97\xcd\xd2\x18\x34\x83\xa5\x15\x4a\xd0\xb9\x8d\xe0\xcc\x87\xb5\xf3\x7e\x6e\xa0\x19\x8d\xd3\x71\x5b\xcf\xce\x63\xb8\xc1\x58\xe4\x06\xe1\x32\x43\xf5\x39\x21\x73\x7b\x3f\x04\x90\x81\x15\x19\x43\x6a\xd9\x61\x9a\x6e\x8a\x7c\x02\x65\xd7\xfd\x88\x8c\x4a\x48\x17\x21\xee\xa3\x28\x46\x0e\x9e\xca\xfb\x78\xd2\x83\x40\x57\x4d\x07\x8f\x74\xd4\x0a\x70\x36\x4b\x51\x18\xbc\x1f\xf7\xe0\xf3\xf4\xe2\xd3\xfb\x7f\xcf\x67\x17\x53\x97\x23\x49\x41\x74\x67\x6e\x33\xd6\x99\x81\x5c\x49\x64\xcf\xc9\x6c\x8c\xc5\x15\x4c\x2f\x7f\x9f\x80\x24\xc6\xd8\x6a\xde\x44\xa1\xf8\x7a\x51\x0a\xbd\x18\xe1\xce\xed\x8d\x44\x46\xde\xe0\x51\xc6\xda\xea\xf1\xe3\x55\x25\x56\xe8\xf3\xe7\xe3\xdd\x9a\xad\x63\x5d\x2e\x3b\xde\xc8\x96\xf0\x21\xf9\xcb\xd9\xe4\x63\x79\xca\x67\x32\xe1\x77\x3c\x40\xf0\xd9\xe2\xc1\x9c\xb0\x7b\x68\x08\xf3\x3f\xbf\x9a\x4f\xfb\x09\xbe\x97\xd0\xdc\xcb\x91\x2d\x1a\x4b\x6a\x19\xf9\xb7\x16\x61\xbc\xc2\x4c\xd8\x04\xf4\xa2\xcc\x03\x4f\xf1\x16\x94\x62\xdf\x91\xf6\xea\x69\xd7\x24\xee\x54\x8b\x72\x3e\xfd\xeb\xa7\x1f\x7f\xf6\xea\x32\x41\x5c\x3d\x29\xd8\x6f\x86\x7b\x46\x61\xb4\x6a\x91\xab\x4f\x02\x0f\xd6\xf4\x77\xc8\x76\x67\x94\xc7\x8f\x6f\x11\x3c\x35\x78\x82\x7b\x77\xec\x25\xc3\xaf\x6b\x3d\x2a\x08\x1e\xe0\xfd\xde\xa9\x2d\xe1\x2b\xc1\xe3\xf4\x7b\x81\x77\xe4\x07\xff\x0c\xfe\x0f\x00\x00\xff\xff\xab\x99\xa5\xc1\xc1\x1b\x00\x00")
+var _wski18nResourcesEn_usAllJson = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\x03\xcd\x58\x4b\x6f\xe3\x36\x10\xbe\xe7\x57\x0c\x7c\x49\x0a\x64\x85\x5e\x7a\x68\x7a\x0a\x52\xa3\x0e\x76\xbb\x09\xba\x4e\xb7\x40\x53\x14\x8c\x34\x4a\x88\xc8\xa4\x4a\x52\x49\xbd\x81\xff\x7b\x67\x28\xc9\xf6\x26\xa6\x5e\xd6\xa6\x39\x2c\x56\xa1\x39\xdf\x7c\x9c\x19\xce\x83\x7f\x1e\x00\x3c\xd1\x3f\x80\x89\x4c\x26\x27\x30\xb9\x52\xe2\x26\x43\x70\x1a\x44\x92\x80\xd1\x85\x43\xd0\xb9\x93\x5a\x59\x38\x7c\x7a\x8a\xaa\xef\xd5\xea\x70\x72\x5c\xca\x39\x23\x94\xcd\x04\x2f\xb7\x00\x9c\xc0\x36\xc0\x84\xc4\x57\xc7\x61\xfd\xb1\x41\x41\xb2\xb3\xf9\xfc\x12\x0c\xfe\x53\xa0\x75\x90\x6a\x03\x97\x57\x73\xcf\xc4\x43\x13\x0f\x8f\x8a\xc6\x10\x62\x1b\xa3\x01\x90\x03\x49\xfe\x32\x1d\x9d\x64\x03\xe4\x40\x92\x3f\x4f\x3f\x4c\xe7\xd3\xb1\x79\x36\xa3\x0e\x75\xfa\xc5\xa7\xf1\xbd\xde\x80\xd9\x42\x53\xe4\x39\xaa\x24\x70\x31\x78\xc3\xd5\x6f\x1f\xaa\xd8\x1f\x48\x7a\x7f\x0d\xdd\x2c\x5d\x1b\x84\xe1\x18\xa8\x30\xd9\x20\xeb\xb6\xe2\xec\xa4\x73\xae\x1e\x44\x26\x93\xa1\x2c\x3a\x8b\xef\x54\x3e\x35\x86\xa2\x00\x55\xac\x13\xa9\x6e\xd7\x20\x37\x3a\x59\xb6\x6a\xee\x26\xdb\xa0\x56\x2a\xe9\x24\x91\xff\xb2\x25\xde\x51\x6b\x8b\x68\x5b\xe8\x52\x56\x76\x77\xd5\x75\x10\x05\x7d\x2a\x27\x63\xaf\x02\xee\x50\x24\x68\xfa\x44\x69\x1f\xb0\x9d\xc4\x4e\x49\x48\x1b\xf9\xa5\x94\xb9\xc7\x25\x48\x0b\x4a\x3b\x88\xb5\x4a\xe5\x6d\x61\x30\x81\xa3\x77\xef\x18\x9b\x7f\xe1\xe3\x4a\x5a\xfb\x2e\x40\x6d\x30\xdc\x6e\x72\x0a\x4e\x2f\xcf\xe1\x4e\x93\x6b\x17\x05\xfb\x17\x21\x37\xfa\x41\x26\x98\x44\xd7\x2a\xc4\xa1\x45\xaa\x83\x83\x5e\xbf\xee\x9e\xe9\xc5\x42\x50\xc2\x49\x85\xcc\xc8\x46\x49\x51\x42\xa9\xd2\xb5\xbc\x4a\xb6\x0b\xa8\xee\x26\xbb\x53\xed\x47\x4d\xe1\xec\xd0\xa4\x22\xde\x18\xe9\x27\xf2\x58\x9d\xae\x6d\x4e\xa4\xd1\x5f\x2c\xc0\x7f\x73\x8c\x1d\x26\x01\x1a\xc3\xb0\xfa\x59\xc3\x2b\x50\x22\x1b\x6a\x91\x17\xf2\x3b\xd5\xcf\xe9\x4a\xa5\x3a\xcb\xf4\x23\x5f\x71\x2a\x05\x59\x7d\xa9\xd0\x67\x80\x47\xc1\xa1\x1b\xa3\x7c\xc0\xa4\xf5\xb6\x0e\x04\x7b\x7b\xf9\xfa\xcd\xe6\xb0\x0d\x16\x1f\x28\x17\xc6\x96\x45\xf9\x01\x8d\x25\x94\x7e\xf5\xb4\x03\xc4\x9e\x3d\xe9\xd0\x12\xdf\x1d\x70\x38\xc1\x71\x58\x75\xa7\x72\x53\xc8\xec\xab\x78\xec\x41\xa0\x49\xb6\x9b\x05\xd8\x80\x2f\xf8\xef\xd7\xe5\xf6\x81\xec\x46\x92\x87\xa3\x91\x49\xf6\x81\xec\x46\xb2\x1a\x3a\x46\xe6\xd9\x13\xb5\xa3\x3d\x79\xec\x18\xdb\xa0\x7d\x30\x03\x99\xbd\x2a\x4c\xbe\x28\x44\x50\xa7\x6a\xeb\xc4\xba\x91\xf0\x80\x7e\x81\x00\x23\xf8\xdd\x6f\xa8\x9b\x14\x61\x10\xae\x27\x22\x76\x54\x47\xae\x27\xc0\xd5\xef\x7a\x22\x55\xbd\x10\x05\x4b\xc2\xb7\xd6\xdb\xe2\x95\x32\xd7\xd6\x95\x68\x80\x0b\x5a\x01\xda\x08\x18\x1d\xa3\xb5\x1e\x81\x7c\x67\x96\x81\xbe\xaf\x0f\xa5\xfe\x90\x3b\x49\xd2\xaf\x0b\x7b\xbb\x5a\xc1\x11\xcd\x39\xc8\x9b\xf9\xff\xd5\x2a\xd4\x7d\x87\xf7\x07\xdb\x1c\xea\xcb\x15\x35\x62\xec\xe5\xb2\x59\x3a\x06\x8a\x5c\x27\x17\xd4\x36\x91\x29\x23\x38\xf2\x61\xcd\xde\x2f\x2c\x74\xa3\xb1\x3f\x6e\xef\xd9\xf9\x98\x9a\xfc\x58\x14\x14\x08\x17\x34\xb7\x7f\xbe\x93\xf6\x7e\x33\x04\xd0\xa0\xb1\x90\xd6\x52\xfb\x35\x60\x9a\xee\x8a\xbc\x07\x65\xee\x7e\x44\x2e\x4b\x48\x8e\x10\xfe\xe0\x67\x06\x82\x97\xe5\x7d\xdc\xeb\x41\x60\xa8\xa6\x9d\x47\x6a\xb4\x02\x1c\x5d\x66\x28\x2c\x6e\xc6\x3d\xf8\x3c\x3b\xff\xf4\xfe\x6f\xda\x3b\xe3\x1c\x29\x15\x44\x8f\xf6\x9e\x2e\x48\x6e\xa1\x50\xd4\xdc\x79\x4e\x76\x69\x1d\x2e\x60\x76\xf1\xeb\x14\x12\x1a\x08\x63\xa7\xcd\x32\x0a\xc5\xd7\xab\x52\x18\xc5\x08\x8f\xbc\x37\x22\xcb\x7b\x83\x47\xa4\xdb\xe9\xe3\xe7\xab\x4a\x2c\xd0\xe7\xcf\xe7\xbb\xb5\x71\xcc\xba\x5c\x66\xde\x68\x9c\xc4\x6d\xf2\x17\x97\xd3\x8f\xe5\x29\xbf\x91\x09\xff\xc7\x03\x04\x9f\x2d\xb6\xe6\x84\xea\xa1\x21\xcc\xff\xf4\x6a\x3e\x1b\x27\xf8\x5e\x43\xf3\x28\x47\x76\x74\xfb\xe9\x97\xc8\xbf\xb5\xd0\x8c\xc9\x0a\x73\x41\xdf\x3a\x2d\xf3\xc0\x4b\xbc\x94\x12\xf5\xd8\x91\xf6\xe6\x69\xb7\x24\xee\x4c\x8b\x72\x3e\xfd\xe3\x87\xef\x7f\xf4\xea\x72\x21\x4d\xfd\xa4\xe0\xbe\x1a\xee\x29\xf3\x5a\xad\x7a\xe4\xea\xbd\xc0\x83\x35\xfd\x8c\x8e\x5f\x19\xe5\xf9\xe3\x5b\x04\x2f\x0d\x4e\x12\xb5\x3b\xd6\x92\xe1\xd7\xb5\x11\x15\x04\x0f\xf0\x7e\xed\xd4\x9e\xf0\xb5\x60\x33\xfd\x51\xe0\x1b\xdf\x67\xc8\x47\xe4\x4c\x6a\xc4\xe8\xcf\xa2\x7a\x49\xe0\x2f\x2e\xb5\xa9\xd1\x0b\xde\x60\x75\x61\x62\x5a\x39\x59\xbf\x85\x81\x33\xb4\x99\xda\xa4\x54\x64\x16\x5b\xde\x70\x46\x51\xd1\x12\xfb\x06\x29\xf2\xca\x00\xdd\x7e\x61\xf7\xc3\x8d\xa0\x25\x67\x96\x3d\x82\xbd\x1f\x5a\x0b\xb5\x5b\x74\x15\x92\x2d\x32\xc7\xc9\xc1\x4f\x1c\x62\x3d\xaf\xc8\xa4\x5f\xcf\xde\x1b\x30\x18\xbd\x94\x80\x7c\x74\xb1\x50\xf5\x5d\xb5\x58\x1c\x6c\x09\xa6\x52\x91\x23\x64\x89\xca\xa9\x2d\xfc\xa8\x3c\x18\xae\x31\x3a\xab\x0c\xb9\x04\x5f\xb0\x0f\xeb\x48\x3a\x6c\x89\xb8\xa0\x58\x9b\x32\x4f\x7f\x2d\xb4\x39\x43\xbb\xbe\xa0\x64\xd0\xf8\xe5\x6d\x20\xef\x6d\xf8\x41\x2c\x14\x9b\xea\x86\xda\x18\x23\x9d\x43\xaa\x14\xd2\xdd\xd1\x14\x02\x68\x63\x91\xa3\x6d\xb0\xfe\x30\xbc\xae\x35\xe5\xec\x14\x62\xae\x55\x29\x97\x33\x2a\x58\x23\x57\x95\x61\xf0\x3b\xc9\x2b\xbd\x0d\x45\xe2\x54\x5b\xeb\xa0\x6b\x76\x67\x17\x49\x56\x79\xf0\xd7\xc1\x7f\xc9\x41\xf2\x5e\x0e\x21\x00\x00")
 
 func wski18nResourcesEn_usAllJsonBytes() ([]byte, error) {
 	return bindataRead(
@@ -129,7 +129,7 @@ func wski18nResourcesEn_usAllJson() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "wski18n/resources/en_US.all.json", size: 7105, mode: os.FileMode(420), modTime: time.Unix(1510603813, 0)}
+	info := bindataFileInfo{name: "wski18n/resources/en_US.all.json", size: 8462, mode: os.FileMode(420), modTime: time.Unix(1510603813, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }